@@ -2,20 +2,19 @@ package fifo
 
 import (
 	"fmt"
+	"github.com/yuhao-jack/go-toolx/containerx"
 	"strings"
 )
 
 type FifoCache[K comparable, V any] struct {
-	capacity   int
-	size       int
-	cache      map[K]*FifoNode[K, V]
-	head, tail *FifoNode[K, V]
+	capacity int
+	cache    map[K]*containerx.Element[FifoNode[K, V]]
+	list     *containerx.List[FifoNode[K, V]] // 头部是最新插入的节点
 }
 
 type FifoNode[K comparable, V any] struct {
-	Key        K
-	Val        V
-	Prev, Next *FifoNode[K, V]
+	Key K
+	Val V
 }
 
 // NewFifoCache [K comparable, V any]
@@ -24,17 +23,11 @@ type FifoNode[K comparable, V any] struct {
 //	@param capacity 缓存的数量
 //	@return *FifoCache[K, V]
 func NewFifoCache[K comparable, V any](capacity int) *FifoCache[K, V] {
-	fifoCache := FifoCache[K, V]{
+	return &FifoCache[K, V]{
 		capacity: capacity,
-		size:     0,
-		cache:    map[K]*FifoNode[K, V]{},
-		head:     &FifoNode[K, V]{},
-		tail:     &FifoNode[K, V]{},
+		cache:    map[K]*containerx.Element[FifoNode[K, V]]{},
+		list:     containerx.NewList[FifoNode[K, V]](),
 	}
-
-	fifoCache.head.Next = fifoCache.tail
-	fifoCache.tail.Prev = fifoCache.head
-	return &fifoCache
 }
 
 // String
@@ -47,7 +40,8 @@ func (l *FifoCache[K, V]) String() string {
 		return ""
 	}
 	sb := strings.Builder{}
-	for _, f := range l.cache {
+	for e := l.list.Front(); e != nil; e = e.Next() {
+		f := e.Value
 		if sb.Len() == 0 {
 			sb.WriteString(fmt.Sprint("{", f.Key, "=", f.Val))
 		} else {
@@ -74,7 +68,7 @@ func (l *FifoCache[K, V]) Get(key K, defaultVal ...V) (V, bool) {
 		var v V
 		return v, ok
 	}
-	return node.Val, ok
+	return node.Value.Val, ok
 }
 
 // Put
@@ -86,59 +80,13 @@ func (l *FifoCache[K, V]) Get(key K, defaultVal ...V) (V, bool) {
 func (l *FifoCache[K, V]) Put(key K, val V) {
 	node, ok := l.cache[key]
 	if !ok { // 如果 key 不存在，创建一个新的节点
-		newNode := &FifoNode[K, V]{Key: key, Val: val}
-		l.cache[key] = newNode
-		l.addToHead(newNode)
-		l.size++
+		l.cache[key] = l.list.PushFront(FifoNode[K, V]{Key: key, Val: val})
 	} else {
-		node.Val = val
-		l.moveToHead(node)
+		node.Value.Val = val
+		l.list.MoveToFront(node)
 	}
-	if l.size > l.capacity {
-		tail := l.removeTail()
+	if l.list.Len() > l.capacity {
+		tail := l.list.Remove(l.list.Back())
 		delete(l.cache, tail.Key)
 	}
 }
-
-// addToHead
-//
-//	@Description: 添加到头部
-//	@receiver l
-//	@param node 待添加的节点
-func (l *FifoCache[K, V]) addToHead(node *FifoNode[K, V]) {
-	node.Prev = l.head
-	node.Next = l.head.Next
-	l.head.Next.Prev = node
-	l.head.Next = node
-}
-
-// removeTail
-//
-//	@Description: 删除尾节点，先找到尾节点再删除
-//	@receiver l
-//	@return *LruNode[K,V] 被删除的尾节点
-func (l *FifoCache[K, V]) removeTail() *FifoNode[K, V] {
-	res := l.tail.Prev
-	l.removeNode(res)
-	return res
-}
-
-// removeNode
-//
-//	@Description: 删除节点
-//	@receiver l
-//	@param node 待删除的节点
-func (l *FifoCache[K, V]) removeNode(node *FifoNode[K, V]) {
-	node.Prev.Next = node.Next
-	node.Next.Prev = node.Prev
-}
-
-// moveToHead
-//
-//	@Description: 把节点移动到头部
-//	@receiver l
-//	@param node 待移动的节点
-func (l *FifoCache[K, V]) moveToHead(node *FifoNode[K, V]) {
-	l.removeNode(node)
-	l.addToHead(node)
-}
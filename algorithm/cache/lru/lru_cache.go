@@ -1,16 +1,17 @@
 package lru
 
+import "github.com/yuhao-jack/go-toolx/containerx"
+
 type LruNode[K comparable, V any] struct {
-	Key        K
-	Val        V
-	Prev, Next *LruNode[K, V]
+	Key K
+	Val V
 }
 
 type LruCache[K comparable, V any] struct {
-	Size       int //节点的数量
-	Cap        int // 容量
-	Cache      map[K]*LruNode[K, V]
-	Head, Tail *LruNode[K, V] //头尾节点
+	Size  int // 节点的数量
+	Cap   int // 容量
+	Cache map[K]*containerx.Element[LruNode[K, V]]
+	List  *containerx.List[LruNode[K, V]] // 头部是最近使用的节点
 }
 
 // NewLruCache [K comparable, V any]
@@ -19,16 +20,12 @@ type LruCache[K comparable, V any] struct {
 //	@param cap 缓存的数量
 //	@return *LruCache[K，V]
 func NewLruCache[K comparable, V any](cap int) *LruCache[K, V] {
-	lruCache := &LruCache[K, V]{
+	return &LruCache[K, V]{
 		Size:  0,
 		Cap:   cap,
-		Cache: map[K]*LruNode[K, V]{},
-		Head:  &LruNode[K, V]{},
-		Tail:  &LruNode[K, V]{},
+		Cache: map[K]*containerx.Element[LruNode[K, V]]{},
+		List:  containerx.NewList[LruNode[K, V]](),
 	}
-	lruCache.Head.Next = lruCache.Tail
-	lruCache.Tail.Prev = lruCache.Head
-	return lruCache
 }
 
 // Get
@@ -47,8 +44,8 @@ func (l *LruCache[K, V]) Get(key K, defaultVal ...V) (V, bool) {
 		return v, ok
 	}
 	// 如果 key 存在，先通过哈希表定位，再移到头部
-	l.moveToHead(node)
-	return node.Val, ok
+	l.List.MoveToFront(node)
+	return node.Value.Val, ok
 }
 
 // Put
@@ -60,63 +57,19 @@ func (l *LruCache[K, V]) Get(key K, defaultVal ...V) (V, bool) {
 func (l *LruCache[K, V]) Put(key K, val V) {
 	node, ok := l.Cache[key]
 	if !ok { // 如果 key 不存在，创建一个新的节点
-		newNode := &LruNode[K, V]{Key: key, Val: val}
-		l.Cache[key] = newNode // 添加进哈希表
-		l.addToHead(newNode)
+		// 添加到链表头部和哈希表
+		l.Cache[key] = l.List.PushFront(LruNode[K, V]{Key: key, Val: val})
 		l.Size++
 		if l.Size > l.Cap {
 			// 如果超出容量，删除双向链表的尾部节点
-			tail := l.removeTail()
+			tail := l.List.Remove(l.List.Back())
 			// 删除哈希表中对应的项
 			delete(l.Cache, tail.Key)
 			l.Size--
 		}
 	} else {
-		node.Val = val
+		node.Value.Val = val
 		// 如果 key 存在，先通过哈希表定位，再修改 value，并移到头部
-		l.moveToHead(node)
+		l.List.MoveToFront(node)
 	}
 }
-
-// addToHead
-//
-//	@Description: 添加到头部
-//	@receiver l
-//	@param node 待添加的节点
-func (l *LruCache[K, V]) addToHead(node *LruNode[K, V]) {
-	node.Prev = l.Head
-	node.Next = l.Head.Next
-	l.Head.Next.Prev = node
-	l.Head.Next = node
-}
-
-// removeNode
-//
-//	@Description: 删除节点
-//	@receiver l
-//	@param node 待删除的节点
-func (l *LruCache[K, V]) removeNode(node *LruNode[K, V]) {
-	node.Prev.Next = node.Next
-	node.Next.Prev = node.Prev
-}
-
-// moveToHead
-//
-//	@Description: 把节点移动到头部
-//	@receiver l
-//	@param node 待移动的节点
-func (l *LruCache[K, V]) moveToHead(node *LruNode[K, V]) {
-	l.removeNode(node)
-	l.addToHead(node)
-}
-
-// removeTail
-//
-//	@Description: 删除尾节点，先找到尾节点再删除
-//	@receiver l
-//	@return *LruNode[K,V] 被删除的尾节点
-func (l *LruCache[K, V]) removeTail() *LruNode[K, V] {
-	res := l.Tail.Prev
-	l.removeNode(res)
-	return res
-}
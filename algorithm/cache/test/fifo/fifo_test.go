@@ -20,3 +20,17 @@ func TestFifo(t *testing.T) {
 	fmt.Println(cache.String())
 
 }
+
+func TestFifoEvict(t *testing.T) {
+	cache := fifo.NewFifoCache[string, int](2)
+	cache.Put("A", 1)
+	cache.Put("B", 2)
+	cache.Put("C", 3)
+	cache.Put("C", 4)
+	if _, ok := cache.Get("A"); ok {
+		t.Fatal("A should be evicted")
+	}
+	if v, ok := cache.Get("B"); !ok || v != 2 {
+		t.Fatalf("B = %d,%v, updating C should not evict B", v, ok)
+	}
+}
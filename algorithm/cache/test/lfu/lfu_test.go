@@ -14,8 +14,8 @@ func TestLfu(t *testing.T) {
 	lfuCache.Put("D", 2)
 	lfuCache.Put("A", 1)
 	lfuCache.Put("B", 5)
-	v := lfuCache.Get("A", -1)
+	v, _ := lfuCache.Get("A", -1)
 	fmt.Printf("%v\n", v)
-	v = lfuCache.Get("F", -1)
+	v, _ = lfuCache.Get("F", -1)
 	fmt.Printf("%v\n", v)
 }
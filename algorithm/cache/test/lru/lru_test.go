@@ -44,14 +44,14 @@ func TestLru1(t *testing.T) {
 		Age:  15,
 		Sex:  "男",
 	})
-	user := lruCache.Get("A", &User{
+	user, _ := lruCache.Get("A", &User{
 		Name: "xiao hei",
 		Age:  99,
 		Sex:  "女",
 	})
 	fmt.Printf("%v\n", user)
 
-	user = lruCache.Get("M", &User{
+	user, _ = lruCache.Get("M", &User{
 		Name: "xiao hei",
 		Age:  99,
 		Sex:  "女",
@@ -0,0 +1,182 @@
+package search
+
+// DisjointSet [T comparable]
+// @Description: 并查集，使用路径压缩和按秩合并，非并发安全
+type DisjointSet[T comparable] struct {
+	index  map[T]int // 元素到下标的映射
+	items  []T
+	parent []int
+	rank   []int
+	size   []int // 只有根节点的值有效
+	count  int   // 集合个数
+}
+
+// NewDisjointSet [T comparable]
+//
+//	@Description: 创建并查集
+//	@param items 初始的元素，每个元素单独成为一个集合
+//	@return *DisjointSet[T]
+func NewDisjointSet[T comparable](items ...T) *DisjointSet[T] {
+	d := &DisjointSet[T]{index: make(map[T]int, len(items))}
+	for _, item := range items {
+		d.Add(item)
+	}
+	return d
+}
+
+// Add
+//
+//	@Description: 添加元素，已存在时忽略
+//	@receiver d
+//	@param x
+//	@return bool 是否是新添加的
+func (d *DisjointSet[T]) Add(x T) bool {
+	if _, ok := d.index[x]; ok {
+		return false
+	}
+	i := len(d.items)
+	d.index[x] = i
+	d.items = append(d.items, x)
+	d.parent = append(d.parent, i)
+	d.rank = append(d.rank, 0)
+	d.size = append(d.size, 1)
+	d.count++
+	return true
+}
+
+// Contains
+//
+//	@Description:
+//	@receiver d
+//	@param x
+//	@return bool
+func (d *DisjointSet[T]) Contains(x T) bool {
+	_, ok := d.index[x]
+	return ok
+}
+
+// Find
+//
+//	@Description: 查找x所在集合的代表元素，x不存在时先添加
+//	@receiver d
+//	@param x
+//	@return T
+func (d *DisjointSet[T]) Find(x T) T {
+	d.Add(x)
+	return d.items[d.find(d.index[x])]
+}
+
+// find
+//
+//	@Description: 查找根节点并压缩路径
+//	@receiver d
+//	@param i
+//	@return int
+func (d *DisjointSet[T]) find(i int) int {
+	root := i
+	for d.parent[root] != root {
+		root = d.parent[root]
+	}
+	for d.parent[i] != root {
+		i, d.parent[i] = d.parent[i], root
+	}
+	return root
+}
+
+// Union
+//
+//	@Description: 合并x和y所在的集合，不存在的元素会先添加
+//	@receiver d
+//	@param x
+//	@param y
+//	@return bool 合并前是否属于不同的集合
+func (d *DisjointSet[T]) Union(x, y T) bool {
+	d.Add(x)
+	d.Add(y)
+	rx, ry := d.find(d.index[x]), d.find(d.index[y])
+	if rx == ry {
+		return false
+	}
+	if d.rank[rx] < d.rank[ry] {
+		rx, ry = ry, rx
+	}
+	d.parent[ry] = rx
+	d.size[rx] += d.size[ry]
+	if d.rank[rx] == d.rank[ry] {
+		d.rank[rx]++
+	}
+	d.count--
+	return true
+}
+
+// Connected
+//
+//	@Description: x和y是否在同一个集合中
+//	@receiver d
+//	@param x
+//	@param y
+//	@return bool 任意一个不存在时为false
+func (d *DisjointSet[T]) Connected(x, y T) bool {
+	ix, ok := d.index[x]
+	if !ok {
+		return false
+	}
+	iy, ok := d.index[y]
+	if !ok {
+		return false
+	}
+	return d.find(ix) == d.find(iy)
+}
+
+// Size
+//
+//	@Description: x所在集合的元素个数
+//	@receiver d
+//	@param x
+//	@return int x不存在时为0
+func (d *DisjointSet[T]) Size(x T) int {
+	i, ok := d.index[x]
+	if !ok {
+		return 0
+	}
+	return d.size[d.find(i)]
+}
+
+// Count
+//
+//	@Description: 集合的个数
+//	@receiver d
+//	@return int
+func (d *DisjointSet[T]) Count() int {
+	return d.count
+}
+
+// Len
+//
+//	@Description: 元素的个数
+//	@receiver d
+//	@return int
+func (d *DisjointSet[T]) Len() int {
+	return len(d.items)
+}
+
+// Groups
+//
+//	@Description: 按元素的添加顺序返回所有集合
+//	@receiver d
+//	@return [][]T
+func (d *DisjointSet[T]) Groups() [][]T {
+	groupIndex := make(map[int]int, d.count)
+	groups := make([][]T, 0, d.count)
+	for i, item := range d.items {
+		root := d.find(i)
+		g, ok := groupIndex[root]
+		if !ok {
+			g = len(groups)
+			groupIndex[root] = g
+			groups = append(groups, make([]T, 0, d.size[root]))
+		}
+		groups[g] = append(groups[g], item)
+	}
+	return groups
+}
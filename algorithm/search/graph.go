@@ -0,0 +1,447 @@
+// Package search
+// @Description: 搜索与图算法
+package search
+
+import (
+	"errors"
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"math"
+)
+
+var (
+	ErrCycle          = errors.New("graph has a cycle")
+	ErrNegativeWeight = errors.New("graph has a negative edge weight")
+	ErrVertexNotFound = errors.New("vertex not found")
+)
+
+// Graph [T comparable]
+// @Description: 带权图，支持有向和无向，顶点和邻居都按添加顺序遍历，保证算法结果确定，非并发安全
+type Graph[T comparable] struct {
+	directed bool
+	adj      *containerx.LinkedMap[T, *containerx.LinkedMap[T, float64]]
+}
+
+// NewDirectedGraph [T comparable]
+//
+//	@Description: 创建有向图
+//	@return *Graph[T]
+func NewDirectedGraph[T comparable]() *Graph[T] {
+	return &Graph[T]{
+		directed: true,
+		adj:      containerx.NewLinkedMap[T, *containerx.LinkedMap[T, float64]](),
+	}
+}
+
+// NewUndirectedGraph [T comparable]
+//
+//	@Description: 创建无向图
+//	@return *Graph[T]
+func NewUndirectedGraph[T comparable]() *Graph[T] {
+	return &Graph[T]{
+		adj: containerx.NewLinkedMap[T, *containerx.LinkedMap[T, float64]](),
+	}
+}
+
+// IsDirected
+//
+//	@Description:
+//	@receiver g
+//	@return bool
+func (g *Graph[T]) IsDirected() bool {
+	return g.directed
+}
+
+// AddVertex
+//
+//	@Description: 添加顶点，已存在时忽略
+//	@receiver g
+//	@param v
+//	@return *Graph[T]
+func (g *Graph[T]) AddVertex(v T) *Graph[T] {
+	g.neighbors(v)
+	return g
+}
+
+// AddEdge
+//
+//	@Description: 添加边，顶点不存在时自动添加，边已存在时更新权重
+//	@receiver g
+//	@param from
+//	@param to
+//	@param weight 权重，默认为1
+//	@return *Graph[T]
+func (g *Graph[T]) AddEdge(from, to T, weight ...float64) *Graph[T] {
+	w := 1.0
+	if len(weight) > 0 {
+		w = weight[0]
+	}
+	g.neighbors(from).Set(to, w)
+	if g.directed {
+		g.neighbors(to)
+	} else {
+		g.neighbors(to).Set(from, w)
+	}
+	return g
+}
+
+// RemoveEdge
+//
+//	@Description: 删除边
+//	@receiver g
+//	@param from
+//	@param to
+//	@return bool 边是否存在
+func (g *Graph[T]) RemoveEdge(from, to T) bool {
+	nb, ok := g.adj.Peek(from)
+	if !ok {
+		return false
+	}
+	if _, ok = nb.Remove(to); !ok {
+		return false
+	}
+	if !g.directed {
+		if back, ok := g.adj.Peek(to); ok {
+			back.Remove(from)
+		}
+	}
+	return true
+}
+
+// RemoveVertex
+//
+//	@Description: 删除顶点及与它相连的边
+//	@receiver g
+//	@param v
+//	@return bool 顶点是否存在
+func (g *Graph[T]) RemoveVertex(v T) bool {
+	if _, ok := g.adj.Remove(v); !ok {
+		return false
+	}
+	g.adj.Each(func(_ T, nb *containerx.LinkedMap[T, float64]) {
+		nb.Remove(v)
+	})
+	return true
+}
+
+// HasVertex
+//
+//	@Description:
+//	@receiver g
+//	@param v
+//	@return bool
+func (g *Graph[T]) HasVertex(v T) bool {
+	return g.adj.Contains(v)
+}
+
+// HasEdge
+//
+//	@Description:
+//	@receiver g
+//	@param from
+//	@param to
+//	@return bool
+func (g *Graph[T]) HasEdge(from, to T) bool {
+	nb, ok := g.adj.Peek(from)
+	return ok && nb.Contains(to)
+}
+
+// Weight
+//
+//	@Description: 边的权重
+//	@receiver g
+//	@param from
+//	@param to
+//	@return float64
+//	@return bool 边不存在时为false
+func (g *Graph[T]) Weight(from, to T) (float64, bool) {
+	nb, ok := g.adj.Peek(from)
+	if !ok {
+		return 0, false
+	}
+	return nb.Peek(to)
+}
+
+// Vertices
+//
+//	@Description: 按添加顺序返回所有顶点
+//	@receiver g
+//	@return []T
+func (g *Graph[T]) Vertices() []T {
+	return g.adj.Keys()
+}
+
+// Neighbors
+//
+//	@Description: 按添加顺序返回v的邻居（有向图中为出边指向的顶点）
+//	@receiver g
+//	@param v
+//	@return []T
+func (g *Graph[T]) Neighbors(v T) []T {
+	nb, ok := g.adj.Peek(v)
+	if !ok {
+		return nil
+	}
+	return nb.Keys()
+}
+
+// Order
+//
+//	@Description: 顶点个数
+//	@receiver g
+//	@return int
+func (g *Graph[T]) Order() int {
+	return g.adj.Len()
+}
+
+// BFS
+//
+//	@Description: 从start开始广度优先遍历，visit返回false时停止
+//	@receiver g
+//	@param start
+//	@param visit
+//	@return error start不存在时返回ErrVertexNotFound
+func (g *Graph[T]) BFS(start T, visit func(v T) bool) error {
+	if !g.adj.Contains(start) {
+		return ErrVertexNotFound
+	}
+	visited := containerx.NewSetOf(start)
+	queue := containerx.NewDeque[T]()
+	queue.PushBack(start)
+	for !queue.IsEmpty() {
+		v, _ := queue.PopFront()
+		if !visit(v) {
+			return nil
+		}
+		for _, n := range g.Neighbors(v) {
+			if !visited.Contains(n) {
+				visited.Add(n)
+				queue.PushBack(n)
+			}
+		}
+	}
+	return nil
+}
+
+// DFS
+//
+//	@Description: 从start开始深度优先（先序）遍历，visit返回false时停止
+//	@receiver g
+//	@param start
+//	@param visit
+//	@return error start不存在时返回ErrVertexNotFound
+func (g *Graph[T]) DFS(start T, visit func(v T) bool) error {
+	if !g.adj.Contains(start) {
+		return ErrVertexNotFound
+	}
+	visited := containerx.NewSet[T]()
+	stack := containerx.NewDeque[T]()
+	stack.PushBack(start)
+	for !stack.IsEmpty() {
+		v, _ := stack.PopBack()
+		if visited.Contains(v) {
+			continue
+		}
+		visited.Add(v)
+		if !visit(v) {
+			return nil
+		}
+		// 逆序压栈，保证按邻居的添加顺序访问
+		neighbors := g.Neighbors(v)
+		for i := len(neighbors) - 1; i >= 0; i-- {
+			if !visited.Contains(neighbors[i]) {
+				stack.PushBack(neighbors[i])
+			}
+		}
+	}
+	return nil
+}
+
+// TopologicalSort
+//
+//	@Description: 拓扑排序（Kahn算法），入度相同时按添加顺序输出，只适用于有向图
+//	@receiver g
+//	@return []T
+//	@return error 存在环或者是无向图时返回ErrCycle
+func (g *Graph[T]) TopologicalSort() ([]T, error) {
+	if !g.directed {
+		return nil, ErrCycle
+	}
+	inDegree := make(map[T]int, g.adj.Len())
+	g.adj.Each(func(_ T, nb *containerx.LinkedMap[T, float64]) {
+		nb.Each(func(to T, _ float64) {
+			inDegree[to]++
+		})
+	})
+	queue := containerx.NewDeque[T]()
+	for _, v := range g.Vertices() {
+		if inDegree[v] == 0 {
+			queue.PushBack(v)
+		}
+	}
+	order := make([]T, 0, g.adj.Len())
+	for !queue.IsEmpty() {
+		v, _ := queue.PopFront()
+		order = append(order, v)
+		for _, n := range g.Neighbors(v) {
+			inDegree[n]--
+			if inDegree[n] == 0 {
+				queue.PushBack(n)
+			}
+		}
+	}
+	if len(order) != g.adj.Len() {
+		return nil, ErrCycle
+	}
+	return order, nil
+}
+
+// HasCycle
+//
+//	@Description: 是否存在环，无向图中的自环也算作环
+//	@receiver g
+//	@return bool
+func (g *Graph[T]) HasCycle() bool {
+	if g.directed {
+		_, err := g.TopologicalSort()
+		return err != nil
+	}
+	visited := containerx.NewSet[T]()
+	type frame struct{ v, parent T }
+	for _, start := range g.Vertices() {
+		if visited.Contains(start) {
+			continue
+		}
+		stack := containerx.NewDeque[frame]()
+		stack.PushBack(frame{v: start, parent: start})
+		visited.Add(start)
+		for !stack.IsEmpty() {
+			f, _ := stack.PopBack()
+			for _, n := range g.Neighbors(f.v) {
+				if n == f.v {
+					return true
+				}
+				if !visited.Contains(n) {
+					visited.Add(n)
+					stack.PushBack(frame{v: n, parent: f.v})
+				} else if n != f.parent {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ConnectedComponents
+//
+//	@Description: 连通分量，有向图按弱连通计算，分量内和分量间都按顶点添加顺序排列
+//	@receiver g
+//	@return [][]T
+func (g *Graph[T]) ConnectedComponents() [][]T {
+	ds := NewDisjointSet(g.Vertices()...)
+	g.adj.Each(func(from T, nb *containerx.LinkedMap[T, float64]) {
+		nb.Each(func(to T, _ float64) {
+			ds.Union(from, to)
+		})
+	})
+	return ds.Groups()
+}
+
+// Dijkstra
+//
+//	@Description: 单源最短路径
+//	@receiver g
+//	@param source
+//	@return dist 可达顶点的最短距离
+//	@return prev 最短路径上每个顶点的前驱
+//	@return err source不存在时返回ErrVertexNotFound，存在负权边时返回ErrNegativeWeight
+func (g *Graph[T]) Dijkstra(source T) (dist map[T]float64, prev map[T]T, err error) {
+	if !g.adj.Contains(source) {
+		return nil, nil, ErrVertexNotFound
+	}
+	g.adj.Range(func(_ T, nb *containerx.LinkedMap[T, float64]) bool {
+		nb.Range(func(_ T, w float64) bool {
+			if w < 0 {
+				err = ErrNegativeWeight
+			}
+			return err == nil
+		})
+		return err == nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type entry struct {
+		v    T
+		dist float64
+	}
+	dist = map[T]float64{source: 0}
+	prev = map[T]T{}
+	done := containerx.NewSet[T]()
+	pq := containerx.NewPriorityQueue(func(a, b entry) bool { return a.dist < b.dist })
+	pq.Push(entry{v: source})
+	for !pq.IsEmpty() {
+		e, _ := pq.Pop()
+		if done.Contains(e.v) {
+			continue
+		}
+		done.Add(e.v)
+		nb, _ := g.adj.Peek(e.v)
+		nb.Each(func(to T, w float64) {
+			nd := e.dist + w
+			if d, ok := dist[to]; !ok || nd < d {
+				dist[to] = nd
+				prev[to] = e.v
+				pq.Push(entry{v: to, dist: nd})
+			}
+		})
+	}
+	return dist, prev, nil
+}
+
+// ShortestPath
+//
+//	@Description: from到to的最短路径
+//	@receiver g
+//	@param from
+//	@param to
+//	@return path 包含首尾顶点，不可达时为nil
+//	@return distance 不可达时为+Inf
+//	@return err 同Dijkstra
+func (g *Graph[T]) ShortestPath(from, to T) (path []T, distance float64, err error) {
+	dist, prev, err := g.Dijkstra(from)
+	if err != nil {
+		return nil, math.Inf(1), err
+	}
+	d, ok := dist[to]
+	if !ok {
+		return nil, math.Inf(1), nil
+	}
+	for v := to; ; {
+		path = append(path, v)
+		if v == from {
+			break
+		}
+		v = prev[v]
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, d, nil
+}
+
+// neighbors
+//
+//	@Description: 获取v的邻接表，不存在时创建
+//	@receiver g
+//	@param v
+//	@return *containerx.LinkedMap[T, float64]
+func (g *Graph[T]) neighbors(v T) *containerx.LinkedMap[T, float64] {
+	nb, ok := g.adj.Peek(v)
+	if !ok {
+		nb = containerx.NewLinkedMap[T, float64]()
+		g.adj.Set(v, nb)
+	}
+	return nb
+}
@@ -0,0 +1,152 @@
+package test
+
+import (
+	"errors"
+	"github.com/yuhao-jack/go-toolx/algorithm/search"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDisjointSet(t *testing.T) {
+	ds := search.NewDisjointSet(1, 2, 3, 4, 5)
+	if ds.Count() != 5 || ds.Len() != 5 {
+		t.Fatalf("count=%d len=%d", ds.Count(), ds.Len())
+	}
+	if !ds.Union(1, 2) || !ds.Union(3, 4) || !ds.Union(2, 4) {
+		t.Fatal("union of different sets should return true")
+	}
+	if ds.Union(1, 3) {
+		t.Fatal("union of the same set should return false")
+	}
+	if !ds.Connected(1, 4) || ds.Connected(1, 5) || ds.Connected(1, 6) {
+		t.Fatal("unexpected connectivity")
+	}
+	if ds.Find(3) != ds.Find(1) {
+		t.Fatal("find should return the same representative")
+	}
+	if ds.Size(2) != 4 || ds.Size(5) != 1 || ds.Size(6) != 0 {
+		t.Fatalf("size=%d,%d,%d", ds.Size(2), ds.Size(5), ds.Size(6))
+	}
+	if ds.Count() != 2 {
+		t.Fatalf("count=%d", ds.Count())
+	}
+	ds.Union(6, 7)
+	want := [][]int{{1, 2, 3, 4}, {5}, {6, 7}}
+	if got := ds.Groups(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("groups=%v", got)
+	}
+}
+
+func TestGraphTraversal(t *testing.T) {
+	g := search.NewUndirectedGraph[string]()
+	g.AddEdge("a", "b").AddEdge("a", "c").AddEdge("b", "d").AddEdge("c", "d").AddEdge("d", "e")
+
+	var bfs, dfs []string
+	if err := g.BFS("a", func(v string) bool { bfs = append(bfs, v); return true }); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(bfs, want) {
+		t.Fatalf("bfs=%v", bfs)
+	}
+	if err := g.DFS("a", func(v string) bool { dfs = append(dfs, v); return true }); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "d", "c", "e"}; !reflect.DeepEqual(dfs, want) {
+		t.Fatalf("dfs=%v", dfs)
+	}
+
+	var stopped []string
+	_ = g.BFS("a", func(v string) bool { stopped = append(stopped, v); return len(stopped) < 2 })
+	if len(stopped) != 2 {
+		t.Fatalf("bfs should stop early, got %v", stopped)
+	}
+	if err := g.DFS("x", func(string) bool { return true }); !errors.Is(err, search.ErrVertexNotFound) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestGraphTopologicalSort(t *testing.T) {
+	g := search.NewDirectedGraph[string]()
+	g.AddEdge("compile", "test").AddEdge("fetch", "compile").AddEdge("test", "deploy").AddEdge("lint", "deploy")
+	order, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"fetch", "lint", "compile", "test", "deploy"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("order=%v", order)
+	}
+	if g.HasCycle() {
+		t.Fatal("dag should not have a cycle")
+	}
+
+	g.AddEdge("deploy", "fetch")
+	if _, err = g.TopologicalSort(); !errors.Is(err, search.ErrCycle) {
+		t.Fatalf("err=%v", err)
+	}
+	if !g.HasCycle() {
+		t.Fatal("expected a cycle")
+	}
+	g.RemoveEdge("deploy", "fetch")
+	if g.HasCycle() {
+		t.Fatal("cycle should be removed")
+	}
+}
+
+func TestGraphUndirectedCycle(t *testing.T) {
+	g := search.NewUndirectedGraph[int]()
+	g.AddEdge(1, 2).AddEdge(2, 3).AddEdge(4, 5)
+	if g.HasCycle() {
+		t.Fatal("forest should not have a cycle")
+	}
+	g.AddEdge(3, 1)
+	if !g.HasCycle() {
+		t.Fatal("expected a cycle")
+	}
+	g.RemoveVertex(3)
+	if g.HasCycle() || g.HasEdge(2, 3) || g.Order() != 4 {
+		t.Fatal("remove vertex should drop its edges")
+	}
+	g.AddEdge(6, 6)
+	if !g.HasCycle() {
+		t.Fatal("self loop is a cycle")
+	}
+}
+
+func TestGraphConnectedComponents(t *testing.T) {
+	g := search.NewDirectedGraph[int]()
+	g.AddEdge(1, 2).AddEdge(3, 2).AddEdge(4, 5).AddVertex(6)
+	want := [][]int{{1, 2, 3}, {4, 5}, {6}}
+	if got := g.ConnectedComponents(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("components=%v", got)
+	}
+}
+
+func TestGraphShortestPath(t *testing.T) {
+	g := search.NewDirectedGraph[string]()
+	g.AddEdge("a", "b", 4).AddEdge("a", "c", 1).AddEdge("c", "b", 2).AddEdge("b", "d", 1).AddEdge("c", "d", 5).AddVertex("z")
+
+	path, dist, err := g.ShortestPath("a", "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "c", "b", "d"}; !reflect.DeepEqual(path, want) || dist != 4 {
+		t.Fatalf("path=%v dist=%v", path, dist)
+	}
+	path, dist, err = g.ShortestPath("a", "a")
+	if err != nil || !reflect.DeepEqual(path, []string{"a"}) || dist != 0 {
+		t.Fatalf("path=%v dist=%v err=%v", path, dist, err)
+	}
+	path, dist, err = g.ShortestPath("a", "z")
+	if err != nil || path != nil || !math.IsInf(dist, 1) {
+		t.Fatalf("path=%v dist=%v err=%v", path, dist, err)
+	}
+	if w, ok := g.Weight("c", "b"); !ok || w != 2 {
+		t.Fatalf("weight=%v", w)
+	}
+
+	g.AddEdge("d", "a", -1)
+	if _, _, err = g.Dijkstra("a"); !errors.Is(err, search.ErrNegativeWeight) {
+		t.Fatalf("err=%v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package containerx
+
+import "errors"
+
+// ErrDuplicateValue BiMap中value已经对应了其他key
+var ErrDuplicateValue = errors.New("value already bound to another key")
+
+// BiMap [K comparable, V comparable]
+// @Description: 双向map，key和value一一对应，可以通过value反查key，非并发安全
+type BiMap[K comparable, V comparable] struct {
+	forward map[K]V
+	inverse map[V]K
+}
+
+// NewBiMap [K comparable, V comparable]
+//
+//	@Description:
+//	@return *BiMap[K, V]
+func NewBiMap[K comparable, V comparable]() *BiMap[K, V] {
+	return &BiMap[K, V]{
+		forward: make(map[K]V, 8),
+		inverse: make(map[V]K, 8),
+	}
+}
+
+// Put
+//
+//	@Description: 设置key对应的value，key已存在时替换旧的value
+//	@receiver r
+//	@param key
+//	@param val
+//	@return error val已经对应其他key时返回ErrDuplicateValue，不做修改
+func (r *BiMap[K, V]) Put(key K, val V) error {
+	if k, ok := r.inverse[val]; ok && k != key {
+		return ErrDuplicateValue
+	}
+	r.ForcePut(key, val)
+	return nil
+}
+
+// ForcePut
+//
+//	@Description: 设置key对应的value，val已经对应其他key时先删除那个key
+//	@receiver r
+//	@param key
+//	@param val
+//	@return *BiMap[K, V]
+func (r *BiMap[K, V]) ForcePut(key K, val V) *BiMap[K, V] {
+	if old, ok := r.forward[key]; ok {
+		delete(r.inverse, old)
+	}
+	if k, ok := r.inverse[val]; ok {
+		delete(r.forward, k)
+	}
+	r.forward[key] = val
+	r.inverse[val] = key
+	return r
+}
+
+// Get
+//
+//	@Description: 通过key查找value
+//	@receiver r
+//	@param key
+//	@return V
+//	@return bool
+func (r *BiMap[K, V]) Get(key K) (V, bool) {
+	v, ok := r.forward[key]
+	return v, ok
+}
+
+// GetKey
+//
+//	@Description: 通过value反查key
+//	@receiver r
+//	@param val
+//	@return K
+//	@return bool
+func (r *BiMap[K, V]) GetKey(val V) (K, bool) {
+	k, ok := r.inverse[val]
+	return k, ok
+}
+
+// ContainsKey
+//
+//	@Description:
+//	@receiver r
+//	@param key
+//	@return bool
+func (r *BiMap[K, V]) ContainsKey(key K) bool {
+	_, ok := r.forward[key]
+	return ok
+}
+
+// ContainsValue
+//
+//	@Description:
+//	@receiver r
+//	@param val
+//	@return bool
+func (r *BiMap[K, V]) ContainsValue(val V) bool {
+	_, ok := r.inverse[val]
+	return ok
+}
+
+// RemoveKey
+//
+//	@Description: 通过key删除
+//	@receiver r
+//	@param key
+//	@return V 被删除的value
+//	@return bool
+func (r *BiMap[K, V]) RemoveKey(key K) (V, bool) {
+	v, ok := r.forward[key]
+	if ok {
+		delete(r.forward, key)
+		delete(r.inverse, v)
+	}
+	return v, ok
+}
+
+// RemoveValue
+//
+//	@Description: 通过value删除
+//	@receiver r
+//	@param val
+//	@return K 被删除的key
+//	@return bool
+func (r *BiMap[K, V]) RemoveValue(val V) (K, bool) {
+	k, ok := r.inverse[val]
+	if ok {
+		delete(r.inverse, val)
+		delete(r.forward, k)
+	}
+	return k, ok
+}
+
+// Inverse
+//
+//	@Description: 返回value到key的视图，与原map共享数据
+//	@receiver r
+//	@return *BiMap[V, K]
+func (r *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	return &BiMap[V, K]{
+		forward: r.inverse,
+		inverse: r.forward,
+	}
+}
+
+// Size
+//
+//	@Description:
+//	@receiver r
+//	@return int
+func (r *BiMap[K, V]) Size() int {
+	return len(r.forward)
+}
+
+// IsEmpty
+//
+//	@Description:
+//	@receiver r
+//	@return bool
+func (r *BiMap[K, V]) IsEmpty() bool {
+	return r.Size() == 0
+}
+
+// Clear
+//
+//	@Description: 清空，Inverse返回的视图也会被清空
+//	@receiver r
+//	@return *BiMap[K, V]
+func (r *BiMap[K, V]) Clear() *BiMap[K, V] {
+	for k := range r.forward {
+		delete(r.forward, k)
+	}
+	for v := range r.inverse {
+		delete(r.inverse, v)
+	}
+	return r
+}
+
+// ForEach
+//
+//	@Description:
+//	@receiver r
+//	@param fx
+func (r *BiMap[K, V]) ForEach(fx func(key K, val V)) {
+	for k, v := range r.forward {
+		fx(k, v)
+	}
+}
+
+// Keys
+//
+//	@Description:
+//	@receiver r
+//	@return []K
+func (r *BiMap[K, V]) Keys() []K {
+	keys := make([]K, 0, len(r.forward))
+	for k := range r.forward {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values
+//
+//	@Description:
+//	@receiver r
+//	@return []V
+func (r *BiMap[K, V]) Values() []V {
+	vals := make([]V, 0, len(r.inverse))
+	for v := range r.inverse {
+		vals = append(vals, v)
+	}
+	return vals
+}
@@ -0,0 +1,416 @@
+package containerx
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/bits"
+)
+
+// ErrInvalidBitSetData BitSet反序列化的数据长度不是8的倍数
+var ErrInvalidBitSetData = errors.New("invalid bitset binary data")
+
+// ErrBitSetIndexOutOfRange BitSet反序列化的位超过了MaxBitSetIndex
+var ErrBitSetIndexOutOfRange = errors.New("bitset index out of range")
+
+// MaxBitSetIndex BitSet允许的最大位，对应512MiB的位数组，避免一个很大的位导致分配失败或者内存耗尽
+const MaxBitSetIndex uint = 1<<32 - 1
+
+// maxBitSetWords 位数组的最大长度
+const maxBitSetWords = MaxBitSetIndex/64 + 1
+
+// BitSet
+// @Description: 紧凑的位集合，适合权限标记和稠密的非负整数集合，按需自动扩容，非并发安全
+type BitSet struct {
+	words []uint64
+}
+
+// NewBitSet
+//
+//	@Description: 创建位集合
+//	@param capacity 预分配的位数，可选，超过MaxBitSetIndex时按MaxBitSetIndex处理
+//	@return *BitSet
+func NewBitSet(capacity ...uint) *BitSet {
+	b := &BitSet{}
+	if len(capacity) > 0 && capacity[0] > 0 {
+		if capacity[0] > MaxBitSetIndex {
+			capacity[0] = MaxBitSetIndex
+		}
+		b.words = make([]uint64, (capacity[0]+63)/64)
+	}
+	return b
+}
+
+// NewBitSetOf
+//
+//	@Description: 使用给定的位创建位集合
+//	@param indexes
+//	@return *BitSet
+func NewBitSetOf(indexes ...uint) *BitSet {
+	b := NewBitSet()
+	for _, i := range indexes {
+		b.Set(i)
+	}
+	return b
+}
+
+// BitSetFromSet
+//
+//	@Description: 从Set[uint]转换
+//	@param s
+//	@return *BitSet
+func BitSetFromSet(s *Set[uint]) *BitSet {
+	b := NewBitSet()
+	s.ForEach(func(item uint) {
+		b.Set(item)
+	})
+	return b
+}
+
+// ToSet
+//
+//	@Description: 转换成Set[uint]
+//	@receiver b
+//	@return *Set[uint]
+func (b *BitSet) ToSet() *Set[uint] {
+	return NewSetOf(b.Indexes()...)
+}
+
+// Set
+//
+//	@Description: 置位
+//	@receiver b
+//	@param i 超过MaxBitSetIndex时panic
+//	@return *BitSet
+func (b *BitSet) Set(i uint) *BitSet {
+	b.grow(i/64 + 1)
+	b.words[i/64] |= 1 << (i % 64)
+	return b
+}
+
+// Clear
+//
+//	@Description: 清除位
+//	@receiver b
+//	@param i
+//	@return *BitSet
+func (b *BitSet) Clear(i uint) *BitSet {
+	if i/64 < uint(len(b.words)) {
+		b.words[i/64] &^= 1 << (i % 64)
+	}
+	return b
+}
+
+// Flip
+//
+//	@Description: 翻转位
+//	@receiver b
+//	@param i 超过MaxBitSetIndex时panic
+//	@return *BitSet
+func (b *BitSet) Flip(i uint) *BitSet {
+	b.grow(i/64 + 1)
+	b.words[i/64] ^= 1 << (i % 64)
+	return b
+}
+
+// Test
+//
+//	@Description: 位是否被置位
+//	@receiver b
+//	@param i
+//	@return bool
+func (b *BitSet) Test(i uint) bool {
+	if i/64 >= uint(len(b.words)) {
+		return false
+	}
+	return b.words[i/64]&(1<<(i%64)) != 0
+}
+
+// Count
+//
+//	@Description: 被置位的位数（popcount）
+//	@receiver b
+//	@return int
+func (b *BitSet) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Len
+//
+//	@Description: 当前分配的位数
+//	@receiver b
+//	@return uint
+func (b *BitSet) Len() uint {
+	return uint(len(b.words)) * 64
+}
+
+// IsEmpty
+//
+//	@Description: 是否没有任何位被置位
+//	@receiver b
+//	@return bool
+func (b *BitSet) IsEmpty() bool {
+	for _, w := range b.words {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ClearAll
+//
+//	@Description: 清除所有位
+//	@receiver b
+//	@return *BitSet
+func (b *BitSet) ClearAll() *BitSet {
+	for i := range b.words {
+		b.words[i] = 0
+	}
+	return b
+}
+
+// NextSet
+//
+//	@Description: 从i开始（包含i）的下一个被置位的位
+//	@receiver b
+//	@param i
+//	@return uint
+//	@return bool 没有时为false
+func (b *BitSet) NextSet(i uint) (uint, bool) {
+	idx := i / 64
+	if idx >= uint(len(b.words)) {
+		return 0, false
+	}
+	w := b.words[idx] >> (i % 64)
+	if w != 0 {
+		return i + uint(bits.TrailingZeros64(w)), true
+	}
+	for idx++; idx < uint(len(b.words)); idx++ {
+		if b.words[idx] != 0 {
+			return idx*64 + uint(bits.TrailingZeros64(b.words[idx])), true
+		}
+	}
+	return 0, false
+}
+
+// ForEach
+//
+//	@Description: 从小到大遍历被置位的位
+//	@receiver b
+//	@param fx
+func (b *BitSet) ForEach(fx func(i uint)) {
+	for idx, w := range b.words {
+		for w != 0 {
+			t := bits.TrailingZeros64(w)
+			fx(uint(idx)*64 + uint(t))
+			w &= w - 1
+		}
+	}
+}
+
+// Indexes
+//
+//	@Description: 从小到大返回被置位的位
+//	@receiver b
+//	@return []uint
+func (b *BitSet) Indexes() []uint {
+	indexes := make([]uint, 0, b.Count())
+	b.ForEach(func(i uint) {
+		indexes = append(indexes, i)
+	})
+	return indexes
+}
+
+// Clone
+//
+//	@Description:
+//	@receiver b
+//	@return *BitSet
+func (b *BitSet) Clone() *BitSet {
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+	return &BitSet{words: words}
+}
+
+// Equal
+//
+//	@Description: 被置位的位是否完全相同，与分配的长度无关
+//	@receiver b
+//	@param other
+//	@return bool
+func (b *BitSet) Equal(other *BitSet) bool {
+	long, short := b.words, other.words
+	if len(short) > len(long) {
+		long, short = short, long
+	}
+	for i, w := range short {
+		if long[i] != w {
+			return false
+		}
+	}
+	for _, w := range long[len(short):] {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// And
+//
+//	@Description: 交集，返回新的位集合
+//	@receiver b
+//	@param other
+//	@return *BitSet
+func (b *BitSet) And(other *BitSet) *BitSet {
+	n := len(b.words)
+	if len(other.words) < n {
+		n = len(other.words)
+	}
+	res := &BitSet{words: make([]uint64, n)}
+	for i := 0; i < n; i++ {
+		res.words[i] = b.words[i] & other.words[i]
+	}
+	return res
+}
+
+// Or
+//
+//	@Description: 并集，返回新的位集合
+//	@receiver b
+//	@param other
+//	@return *BitSet
+func (b *BitSet) Or(other *BitSet) *BitSet {
+	res := b.Clone()
+	res.grow(uint(len(other.words)))
+	for i, w := range other.words {
+		res.words[i] |= w
+	}
+	return res
+}
+
+// Xor
+//
+//	@Description: 对称差，返回新的位集合
+//	@receiver b
+//	@param other
+//	@return *BitSet
+func (b *BitSet) Xor(other *BitSet) *BitSet {
+	res := b.Clone()
+	res.grow(uint(len(other.words)))
+	for i, w := range other.words {
+		res.words[i] ^= w
+	}
+	return res
+}
+
+// AndNot
+//
+//	@Description: 差集（在b中但不在other中），返回新的位集合
+//	@receiver b
+//	@param other
+//	@return *BitSet
+func (b *BitSet) AndNot(other *BitSet) *BitSet {
+	res := b.Clone()
+	for i := 0; i < len(res.words) && i < len(other.words); i++ {
+		res.words[i] &^= other.words[i]
+	}
+	return res
+}
+
+// MarshalBinary
+//
+//	@Description: 序列化为大端序的[]uint64
+//	@receiver b
+//	@return []byte
+//	@return error
+func (b *BitSet) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 8*len(b.words))
+	for i, w := range b.words {
+		binary.BigEndian.PutUint64(data[8*i:], w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary
+//
+//	@Description: 反序列化
+//	@receiver b
+//	@param data
+//	@return error 超过MaxBitSetIndex时返回ErrBitSetIndexOutOfRange
+func (b *BitSet) UnmarshalBinary(data []byte) error {
+	if len(data)%8 != 0 {
+		return ErrInvalidBitSetData
+	}
+	if uint(len(data)/8) > maxBitSetWords {
+		return ErrBitSetIndexOutOfRange
+	}
+	b.words = make([]uint64, len(data)/8)
+	for i := range b.words {
+		b.words[i] = binary.BigEndian.Uint64(data[8*i:])
+	}
+	return nil
+}
+
+// MarshalJSON
+//
+//	@Description: 序列化为被置位的位组成的JSON数组，与Set[uint]的JSON格式一致
+//	@receiver b
+//	@return []byte
+//	@return error
+func (b *BitSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.Indexes())
+}
+
+// UnmarshalJSON
+//
+//	@Description: 从JSON数组反序列化，会覆盖原有的位
+//	@receiver b
+//	@param data
+//	@return error 有位超过MaxBitSetIndex时返回ErrBitSetIndexOutOfRange
+func (b *BitSet) UnmarshalJSON(data []byte) error {
+	var indexes []uint
+	if err := json.Unmarshal(data, &indexes); err != nil {
+		return err
+	}
+	for _, i := range indexes {
+		if i > MaxBitSetIndex {
+			return ErrBitSetIndexOutOfRange
+		}
+	}
+	b.words = nil
+	for _, i := range indexes {
+		b.Set(i)
+	}
+	return nil
+}
+
+// grow
+//
+//	@Description: 保证至少有n个字，超过MaxBitSetIndex对应的长度时panic
+//	@receiver b
+//	@param n
+func (b *BitSet) grow(n uint) {
+	if n <= uint(len(b.words)) {
+		return
+	}
+	if n > maxBitSetWords {
+		panic(ErrBitSetIndexOutOfRange)
+	}
+	if n <= uint(cap(b.words)) {
+		b.words = b.words[:n]
+		return
+	}
+	c := n + n/2
+	if c > maxBitSetWords {
+		c = maxBitSetWords
+	}
+	words := make([]uint64, n, c)
+	copy(words, b.words)
+	b.words = words
+}
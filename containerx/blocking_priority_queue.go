@@ -0,0 +1,176 @@
+package containerx
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrQueueClosed 队列已关闭
+var ErrQueueClosed = errors.New("queue closed")
+
+// BlockingPriorityQueue [T any]
+// @Description: 并发安全的优先队列，Take在队列为空时阻塞，适合调度器使用
+type BlockingPriorityQueue[T any] struct {
+	mu       sync.Mutex
+	queue    *PriorityQueue[T]
+	notEmpty chan struct{} // 有新元素入队或队列关闭时close，唤醒所有等待者
+	closed   bool
+}
+
+// NewBlockingPriorityQueue [T any]
+//
+//	@Description: 创建阻塞优先队列
+//	@param less less(a, b)为true时a的优先级更高
+//	@return *BlockingPriorityQueue[T]
+func NewBlockingPriorityQueue[T any](less func(a, b T) bool) *BlockingPriorityQueue[T] {
+	return &BlockingPriorityQueue[T]{
+		queue:    NewPriorityQueue(less),
+		notEmpty: make(chan struct{}),
+	}
+}
+
+// Push
+//
+//	@Description: 入队并唤醒等待的Take
+//	@receiver q
+//	@param val
+//	@return *PriorityQueueItem[T] 元素句柄
+//	@return error 队列已关闭时返回ErrQueueClosed
+func (q *BlockingPriorityQueue[T]) Push(val T) (*PriorityQueueItem[T], error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil, ErrQueueClosed
+	}
+	item := q.queue.Push(val)
+	q.broadcast()
+	return item, nil
+}
+
+// Take
+//
+//	@Description: 取出优先级最高的元素，队列为空时阻塞直到有元素、ctx结束或队列关闭
+//	@receiver q
+//	@param ctx
+//	@return T
+//	@return error ctx.Err()或ErrQueueClosed
+func (q *BlockingPriorityQueue[T]) Take(ctx context.Context) (T, error) {
+	q.mu.Lock()
+	for {
+		if val, ok := q.queue.Pop(); ok {
+			q.mu.Unlock()
+			return val, nil
+		}
+		if q.closed {
+			q.mu.Unlock()
+			var t T
+			return t, ErrQueueClosed
+		}
+		wait := q.notEmpty
+		q.mu.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			var t T
+			return t, ctx.Err()
+		}
+		q.mu.Lock()
+	}
+}
+
+// Pop
+//
+//	@Description: 非阻塞地取出优先级最高的元素
+//	@receiver q
+//	@return T
+//	@return bool 队列为空时为false
+func (q *BlockingPriorityQueue[T]) Pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Pop()
+}
+
+// Peek
+//
+//	@Description: 查看优先级最高的元素，不出队
+//	@receiver q
+//	@return T
+//	@return bool 队列为空时为false
+func (q *BlockingPriorityQueue[T]) Peek() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Peek()
+}
+
+// Update
+//
+//	@Description: 修改元素的值并调整位置
+//	@receiver q
+//	@param item
+//	@param val
+//	@return bool item不在当前队列中时为false
+func (q *BlockingPriorityQueue[T]) Update(item *PriorityQueueItem[T], val T) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Update(item, val)
+}
+
+// Fix
+//
+//	@Description: 元素的优先级在外部被修改后调整位置，修改元素时也需要由调用方保证并发安全
+//	@receiver q
+//	@param item
+//	@return bool item不在当前队列中时为false
+func (q *BlockingPriorityQueue[T]) Fix(item *PriorityQueueItem[T]) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Fix(item)
+}
+
+// Remove
+//
+//	@Description: 删除指定元素
+//	@receiver q
+//	@param item
+//	@return T
+//	@return bool item不在当前队列中时为false
+func (q *BlockingPriorityQueue[T]) Remove(item *PriorityQueueItem[T]) (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Remove(item)
+}
+
+// Size
+//
+//	@Description:
+//	@receiver q
+//	@return int
+func (q *BlockingPriorityQueue[T]) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Size()
+}
+
+// Close
+//
+//	@Description: 关闭队列，之后Push返回ErrQueueClosed，队列取空后Take返回ErrQueueClosed
+//	@receiver q
+func (q *BlockingPriorityQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.broadcast()
+}
+
+// broadcast
+//
+//	@Description: 唤醒所有等待者，调用时需持有q.mu
+//	@receiver q
+func (q *BlockingPriorityQueue[T]) broadcast() {
+	close(q.notEmpty)
+	q.notEmpty = make(chan struct{})
+}
@@ -0,0 +1,333 @@
+// Package bloom
+// @Description: 布隆过滤器，用于缓存前的快速否定判断
+package bloom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/bits"
+)
+
+var (
+	ErrIncompatible = errors.New("bloom: filters have different parameters")
+	ErrInvalidData  = errors.New("bloom: invalid binary data")
+)
+
+const (
+	maxHashes   = 255                // hash函数个数的上限，误判率1e-30时也只需要100个
+	maxBits     = math.MaxInt32 * 64 // 反序列化时允许的最大位数
+	maxCounters = math.MaxInt32      // 反序列化时允许的最大计数器个数
+)
+
+// Filter 布隆过滤器，非并发安全
+type Filter struct {
+	m    uint64   // 位数
+	k    uint64   // hash函数个数
+	bits []uint64 // 位数组
+}
+
+// EstimateParameters
+//
+//	@Description: 根据预期元素个数和误判率计算位数和hash函数个数
+//	@param n 预期元素个数
+//	@param fp 误判率，范围(0, 1)
+//	@return m 位数
+//	@return k hash函数个数
+func EstimateParameters(n uint, fp float64) (m uint, k uint) {
+	if n == 0 {
+		n = 1
+	}
+	if fp <= 0 || fp >= 1 {
+		fp = 0.01
+	}
+	m = uint(math.Ceil(-1 * float64(n) * math.Log(fp) / (math.Ln2 * math.Ln2)))
+	k = uint(math.Ceil(math.Ln2 * float64(m) / float64(n)))
+	return m, k
+}
+
+// New
+//
+//	@Description: 创建布隆过滤器
+//	@param m 位数，小于1时按1处理
+//	@param k hash函数个数，小于1时按1处理，最多255个
+//	@return *Filter
+func New(m uint, k uint) *Filter {
+	if m < 1 {
+		m = 1
+	}
+	if k < 1 {
+		k = 1
+	} else if k > maxHashes {
+		k = maxHashes
+	}
+	return &Filter{
+		m:    uint64(m),
+		k:    uint64(k),
+		bits: make([]uint64, (m+63)/64),
+	}
+}
+
+// NewWithEstimates
+//
+//	@Description: 根据预期元素个数和误判率创建布隆过滤器
+//	@param n 预期元素个数
+//	@param fp 误判率，范围(0, 1)
+//	@return *Filter
+func NewWithEstimates(n uint, fp float64) *Filter {
+	return New(EstimateParameters(n, fp))
+}
+
+// Cap
+//
+//	@Description: 位数
+//	@receiver f
+//	@return uint
+func (f *Filter) Cap() uint {
+	return uint(f.m)
+}
+
+// K
+//
+//	@Description: hash函数个数
+//	@receiver f
+//	@return uint
+func (f *Filter) K() uint {
+	return uint(f.k)
+}
+
+// Add
+//
+//	@Description: 添加元素
+//	@receiver f
+//	@param data
+//	@return *Filter
+func (f *Filter) Add(data []byte) *Filter {
+	h1, h2 := baseHashes(data)
+	for i := uint64(0); i < f.k; i++ {
+		loc := location(h1, h2, i, f.m)
+		f.bits[loc>>6] |= 1 << (loc & 63)
+	}
+	return f
+}
+
+// AddString
+//
+//	@Description: 添加字符串元素
+//	@receiver f
+//	@param s
+//	@return *Filter
+func (f *Filter) AddString(s string) *Filter {
+	return f.Add([]byte(s))
+}
+
+// Test
+//
+//	@Description: 判断元素是否可能存在，返回false时一定不存在
+//	@receiver f
+//	@param data
+//	@return bool
+func (f *Filter) Test(data []byte) bool {
+	h1, h2 := baseHashes(data)
+	for i := uint64(0); i < f.k; i++ {
+		loc := location(h1, h2, i, f.m)
+		if f.bits[loc>>6]&(1<<(loc&63)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestString
+//
+//	@Description: 判断字符串元素是否可能存在
+//	@receiver f
+//	@param s
+//	@return bool
+func (f *Filter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+// TestAndAdd
+//
+//	@Description: 先判断再添加
+//	@receiver f
+//	@param data
+//	@return bool 添加前元素是否可能存在
+func (f *Filter) TestAndAdd(data []byte) bool {
+	present := f.Test(data)
+	f.Add(data)
+	return present
+}
+
+// Union
+//
+//	@Description: 合并另一个参数相同的过滤器
+//	@receiver f
+//	@param other
+//	@return error 参数不同时返回ErrIncompatible
+func (f *Filter) Union(other *Filter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrIncompatible
+	}
+	for i := range f.bits {
+		f.bits[i] |= other.bits[i]
+	}
+	return nil
+}
+
+// EstimateCount
+//
+//	@Description: 根据被置位的位数估算已添加的元素个数
+//	@receiver f
+//	@return uint
+func (f *Filter) EstimateCount() uint {
+	ones := 0
+	for _, w := range f.bits {
+		ones += bits.OnesCount64(w)
+	}
+	m, k := float64(f.m), float64(f.k)
+	if uint64(ones) >= f.m {
+		return uint(m / k)
+	}
+	return uint(-m / k * math.Log(1-float64(ones)/m))
+}
+
+// Clear
+//
+//	@Description: 清空
+//	@receiver f
+//	@return *Filter
+func (f *Filter) Clear() *Filter {
+	for i := range f.bits {
+		f.bits[i] = 0
+	}
+	return f
+}
+
+// MarshalBinary
+//
+//	@Description: 序列化，格式为大端序的 m(uint64) k(uint64) 位数组([]uint64)，可以直接作为netx消息的包体
+//	@receiver f
+//	@return []byte
+//	@return error
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 16+8*len(f.bits))
+	binary.BigEndian.PutUint64(data[0:], f.m)
+	binary.BigEndian.PutUint64(data[8:], f.k)
+	for i, w := range f.bits {
+		binary.BigEndian.PutUint64(data[16+8*i:], w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary
+//
+//	@Description: 反序列化
+//	@receiver f
+//	@param data
+//	@return error
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 {
+		return ErrInvalidData
+	}
+	m := binary.BigEndian.Uint64(data[0:])
+	k := binary.BigEndian.Uint64(data[8:])
+	if m == 0 || m > maxBits || k == 0 || k > maxHashes {
+		return ErrInvalidData
+	}
+	words := (m + 63) / 64
+	if uint64(len(data)-16) != words*8 {
+		return ErrInvalidData
+	}
+	f.m, f.k = m, k
+	f.bits = make([]uint64, words)
+	for i := range f.bits {
+		f.bits[i] = binary.BigEndian.Uint64(data[16+8*i:])
+	}
+	return nil
+}
+
+// WriteTo
+//
+//	@Description: 以MarshalBinary的格式写入w
+//	@receiver f
+//	@param w
+//	@return int64
+//	@return error
+func (f *Filter) WriteTo(w io.Writer) (int64, error) {
+	data, _ := f.MarshalBinary()
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom
+//
+//	@Description: 从r中读取WriteTo写入的数据
+//	@receiver f
+//	@param r
+//	@return int64
+//	@return error
+func (f *Filter) ReadFrom(r io.Reader) (int64, error) {
+	header := make([]byte, 16, 16+bytes.MinRead)
+	n, err := io.ReadFull(r, header)
+	if err != nil {
+		return int64(n), err
+	}
+	m := binary.BigEndian.Uint64(header[0:])
+	k := binary.BigEndian.Uint64(header[8:])
+	if m == 0 || m > maxBits || k == 0 || k > maxHashes {
+		return int64(n), ErrInvalidData
+	}
+	data, nb, err := readBody(r, header, (m+63)/64*8)
+	if err != nil {
+		return int64(n) + nb, err
+	}
+	return int64(n) + nb, f.UnmarshalBinary(data)
+}
+
+// readBody
+//
+//	@Description: 在header之后读取size字节，内存随实际读到的数据增长，不会因为伪造的头部一次分配很大的空间
+//	@param r
+//	@param header
+//	@param size
+//	@return []byte header和读到的数据
+//	@return int64 读到的字节数
+//	@return error 数据不足size字节时返回io.ErrUnexpectedEOF
+func readBody(r io.Reader, header []byte, size uint64) ([]byte, int64, error) {
+	buf := bytes.NewBuffer(header)
+	nb, err := io.CopyN(buf, r, int64(size))
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return buf.Bytes(), nb, err
+}
+
+// baseHashes
+//
+//	@Description: 用128位FNV-1a生成两个基础hash，其余hash由二者组合得到（Kirsch-Mitzenmacher）
+//	@param data
+//	@return h1
+//	@return h2
+func baseHashes(data []byte) (h1, h2 uint64) {
+	h := fnv.New128a()
+	_, _ = h.Write(data)
+	sum := h.Sum(nil)
+	return binary.BigEndian.Uint64(sum[0:8]), binary.BigEndian.Uint64(sum[8:16])
+}
+
+// location
+//
+//	@Description: 第i个hash函数对应的位置
+//	@param h1
+//	@param h2
+//	@param i
+//	@param m
+//	@return uint64
+func location(h1, h2, i, m uint64) uint64 {
+	return (h1 + i*h2) % m
+}
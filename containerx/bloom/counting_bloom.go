@@ -0,0 +1,268 @@
+package bloom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// CountingFilter 计数布隆过滤器，每个位置使用8位饱和计数器，支持删除，非并发安全
+type CountingFilter struct {
+	m        uint64
+	k        uint64
+	counters []uint8
+}
+
+// NewCounting
+//
+//	@Description: 创建计数布隆过滤器
+//	@param m 计数器个数，小于1时按1处理
+//	@param k hash函数个数，小于1时按1处理，最多255个
+//	@return *CountingFilter
+func NewCounting(m uint, k uint) *CountingFilter {
+	if m < 1 {
+		m = 1
+	}
+	if k < 1 {
+		k = 1
+	} else if k > maxHashes {
+		k = maxHashes
+	}
+	return &CountingFilter{
+		m:        uint64(m),
+		k:        uint64(k),
+		counters: make([]uint8, m),
+	}
+}
+
+// NewCountingWithEstimates
+//
+//	@Description: 根据预期元素个数和误判率创建计数布隆过滤器
+//	@param n 预期元素个数
+//	@param fp 误判率，范围(0, 1)
+//	@return *CountingFilter
+func NewCountingWithEstimates(n uint, fp float64) *CountingFilter {
+	return NewCounting(EstimateParameters(n, fp))
+}
+
+// Cap
+//
+//	@Description: 计数器个数
+//	@receiver f
+//	@return uint
+func (f *CountingFilter) Cap() uint {
+	return uint(f.m)
+}
+
+// K
+//
+//	@Description: hash函数个数
+//	@receiver f
+//	@return uint
+func (f *CountingFilter) K() uint {
+	return uint(f.k)
+}
+
+// Add
+//
+//	@Description: 添加元素，计数器达到255后不再增加
+//	@receiver f
+//	@param data
+//	@return *CountingFilter
+func (f *CountingFilter) Add(data []byte) *CountingFilter {
+	h1, h2 := baseHashes(data)
+	for i := uint64(0); i < f.k; i++ {
+		loc := location(h1, h2, i, f.m)
+		if f.counters[loc] < math.MaxUint8 {
+			f.counters[loc]++
+		}
+	}
+	return f
+}
+
+// AddString
+//
+//	@Description: 添加字符串元素
+//	@receiver f
+//	@param s
+//	@return *CountingFilter
+func (f *CountingFilter) AddString(s string) *CountingFilter {
+	return f.Add([]byte(s))
+}
+
+// Remove
+//
+//	@Description: 删除元素，只有元素可能存在时才会减少计数；已饱和的计数器不会减少，以免产生误删
+//	@receiver f
+//	@param data
+//	@return bool 元素是否可能存在
+func (f *CountingFilter) Remove(data []byte) bool {
+	if !f.Test(data) {
+		return false
+	}
+	h1, h2 := baseHashes(data)
+	for i := uint64(0); i < f.k; i++ {
+		loc := location(h1, h2, i, f.m)
+		if f.counters[loc] < math.MaxUint8 {
+			f.counters[loc]--
+		}
+	}
+	return true
+}
+
+// RemoveString
+//
+//	@Description: 删除字符串元素
+//	@receiver f
+//	@param s
+//	@return bool 元素是否可能存在
+func (f *CountingFilter) RemoveString(s string) bool {
+	return f.Remove([]byte(s))
+}
+
+// Test
+//
+//	@Description: 判断元素是否可能存在，返回false时一定不存在
+//	@receiver f
+//	@param data
+//	@return bool
+func (f *CountingFilter) Test(data []byte) bool {
+	h1, h2 := baseHashes(data)
+	for i := uint64(0); i < f.k; i++ {
+		if f.counters[location(h1, h2, i, f.m)] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestString
+//
+//	@Description: 判断字符串元素是否可能存在
+//	@receiver f
+//	@param s
+//	@return bool
+func (f *CountingFilter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+// Union
+//
+//	@Description: 合并另一个参数相同的过滤器，对应的计数器相加
+//	@receiver f
+//	@param other
+//	@return error 参数不同时返回ErrIncompatible
+func (f *CountingFilter) Union(other *CountingFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrIncompatible
+	}
+	for i, c := range other.counters {
+		sum := int(f.counters[i]) + int(c)
+		if sum > math.MaxUint8 {
+			sum = math.MaxUint8
+		}
+		f.counters[i] = uint8(sum)
+	}
+	return nil
+}
+
+// ToFilter
+//
+//	@Description: 转换成普通布隆过滤器，计数器不为0的位置置位
+//	@receiver f
+//	@return *Filter
+func (f *CountingFilter) ToFilter() *Filter {
+	res := New(uint(f.m), uint(f.k))
+	for i, c := range f.counters {
+		if c > 0 {
+			res.bits[i>>6] |= 1 << (uint(i) & 63)
+		}
+	}
+	return res
+}
+
+// Clear
+//
+//	@Description: 清空
+//	@receiver f
+//	@return *CountingFilter
+func (f *CountingFilter) Clear() *CountingFilter {
+	for i := range f.counters {
+		f.counters[i] = 0
+	}
+	return f
+}
+
+// MarshalBinary
+//
+//	@Description: 序列化，格式为大端序的 m(uint64) k(uint64) 计数器([]uint8)
+//	@receiver f
+//	@return []byte
+//	@return error
+func (f *CountingFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 16+len(f.counters))
+	binary.BigEndian.PutUint64(data[0:], f.m)
+	binary.BigEndian.PutUint64(data[8:], f.k)
+	copy(data[16:], f.counters)
+	return data, nil
+}
+
+// UnmarshalBinary
+//
+//	@Description: 反序列化
+//	@receiver f
+//	@param data
+//	@return error
+func (f *CountingFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 {
+		return ErrInvalidData
+	}
+	m := binary.BigEndian.Uint64(data[0:])
+	k := binary.BigEndian.Uint64(data[8:])
+	if m == 0 || m > maxCounters || k == 0 || k > maxHashes || uint64(len(data)-16) != m {
+		return ErrInvalidData
+	}
+	f.m, f.k = m, k
+	f.counters = make([]uint8, m)
+	copy(f.counters, data[16:])
+	return nil
+}
+
+// WriteTo
+//
+//	@Description: 以MarshalBinary的格式写入w
+//	@receiver f
+//	@param w
+//	@return int64
+//	@return error
+func (f *CountingFilter) WriteTo(w io.Writer) (int64, error) {
+	data, _ := f.MarshalBinary()
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom
+//
+//	@Description: 从r中读取WriteTo写入的数据
+//	@receiver f
+//	@param r
+//	@return int64
+//	@return error
+func (f *CountingFilter) ReadFrom(r io.Reader) (int64, error) {
+	header := make([]byte, 16, 16+bytes.MinRead)
+	n, err := io.ReadFull(r, header)
+	if err != nil {
+		return int64(n), err
+	}
+	m := binary.BigEndian.Uint64(header[0:])
+	k := binary.BigEndian.Uint64(header[8:])
+	if m == 0 || m > maxCounters || k == 0 || k > maxHashes {
+		return int64(n), ErrInvalidData
+	}
+	data, nb, err := readBody(r, header, m)
+	if err != nil {
+		return int64(n) + nb, err
+	}
+	return int64(n) + nb, f.UnmarshalBinary(data)
+}
@@ -1,9 +1,10 @@
 package containerx
 
 import (
+	"encoding/json"
+	"math/bits"
 	"runtime"
 	"sync"
-	"unsafe"
 )
 
 type ConcurrentMapShared[K comparable, V any] struct {
@@ -11,29 +12,98 @@ type ConcurrentMapShared[K comparable, V any] struct {
 	items map[K]V
 }
 
+// ShardCount NewConcurrentMap 默认使用的分片数，只在创建map时读取，修改它不会影响已经创建的map
 var ShardCount = runtime.NumCPU() * 8
 
-// ConcurrentMap 分成ShardCount个分片的map
-type ConcurrentMap[K comparable, V any] []*ConcurrentMapShared[K, V]
+// ConcurrentMap 分成多个分片的map，分片数在创建时确定并保存在实例中
+type ConcurrentMap[K comparable, V any] struct {
+	shards []*ConcurrentMapShared[K, V]
+	mask   uintptr
+	hasher Hasher[K]
+}
+
+// ConcurrentMapOption 创建ConcurrentMap时的可选配置
+type ConcurrentMapOption func(o *concurrentMapOptions)
+
+type concurrentMapOptions struct {
+	shardCapacity int // 每个分片的初始容量
+}
+
+// WithShardCapacity
+//
+//	@Description: 设置每个分片的初始容量
+//	@param capacity
+//	@return ConcurrentMapOption
+func WithShardCapacity(capacity int) ConcurrentMapOption {
+	return func(o *concurrentMapOptions) {
+		if capacity > 0 {
+			o.shardCapacity = capacity
+		}
+	}
+}
 
-func NewConcurrentMap[K comparable, V any]() *ConcurrentMap[K, V] {
-	m := make(ConcurrentMap[K, V], ShardCount)
-	for i := 0; i < ShardCount; i++ {
-		m[i] = &ConcurrentMapShared[K, V]{items: map[K]V{}}
+// NewConcurrentMap [K comparable, V any]
+//
+//	@Description: 使用ShardCount作为分片数创建map
+//	@param opts
+//	@return *ConcurrentMap[K, V]
+func NewConcurrentMap[K comparable, V any](opts ...ConcurrentMapOption) *ConcurrentMap[K, V] {
+	return NewConcurrentMapWithShards[K, V](ShardCount, opts...)
+}
+
+// NewConcurrentMapWithShards [K comparable, V any]
+//
+//	@Description: 指定分片数创建map，分片数会向上取整为2的幂，以便用掩码代替取模
+//	@param shardCount 分片数，小于1时按1处理
+//	@param opts
+//	@return *ConcurrentMap[K, V]
+func NewConcurrentMapWithShards[K comparable, V any](shardCount int, opts ...ConcurrentMapOption) *ConcurrentMap[K, V] {
+	o := concurrentMapOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	n := roundUpPowerOfTwo(shardCount)
+	m := &ConcurrentMap[K, V]{
+		shards: make([]*ConcurrentMapShared[K, V], n),
+		mask:   uintptr(n - 1),
+		hasher: NewHasher[K](),
+	}
+	for i := 0; i < n; i++ {
+		m.shards[i] = &ConcurrentMapShared[K, V]{items: make(map[K]V, o.shardCapacity)}
+	}
+	return m
+}
+
+// roundUpPowerOfTwo
+//
+//	@Description: 向上取整为2的幂
+//	@param n
+//	@return int
+func roundUpPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
 	}
-	return &m
+	return 1 << bits.Len(uint(n-1))
+}
+
+// ShardCount
+//
+//	@Description: 当前map的分片数
+//	@receiver c
+//	@return int
+func (c *ConcurrentMap[K, V]) ShardCount() int {
+	return len(c.shards)
 }
 
 // GetShard
 //
-//	@Description:
+//	@Description: 获取key所在的分片
 //	@receiver c
 //	@param key
 //	@return *ConcurrentMapShared[K
 //	@return V]
 func (c *ConcurrentMap[K, V]) GetShard(key K) *ConcurrentMapShared[K, V] {
-	u := c.hash(key) % uintptr(ShardCount)
-	return (*c)[int(u)]
+	return c.shards[c.hash(key)&c.mask]
 }
 
 // Set
@@ -75,19 +145,194 @@ func (c *ConcurrentMap[K, V]) Get(key K, defaultVal ...V) (V, bool) {
 
 // Each
 //
-//	@Description:
+//	@Description: 遍历所有元素，回调时不持有分片锁，回调中可以安全地访问当前map
 //	@receiver c
 //	@param f
 func (c *ConcurrentMap[K, V]) Each(f func(key K, val V)) {
-	for _, c2 := range *c {
-		c2.Lock()
-		for k, v := range c2.items {
-			f(k, v)
+	c.Range(func(key K, val V) bool {
+		f(key, val)
+		return true
+	})
+}
+
+// Range
+//
+//	@Description: 逐个分片拷贝快照后遍历，回调时不持有分片锁，f返回false时停止遍历
+//	@receiver c
+//	@param f
+func (c *ConcurrentMap[K, V]) Range(f func(key K, val V) bool) {
+	for _, shard := range c.shards {
+		keys, vals := shard.snapshot()
+		for i := range keys {
+			if !f(keys[i], vals[i]) {
+				return
+			}
+		}
+	}
+}
+
+// snapshot
+//
+//	@Description: 在读锁内拷贝分片中的所有元素
+//	@receiver s
+//	@return keys
+//	@return vals
+func (s *ConcurrentMapShared[K, V]) snapshot() (keys []K, vals []V) {
+	s.RLock()
+	defer s.RUnlock()
+	keys = make([]K, 0, len(s.items))
+	vals = make([]V, 0, len(s.items))
+	for k, v := range s.items {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	}
+	return keys, vals
+}
+
+// Len
+//
+//	@Description: 元素个数，各分片分别加锁统计，并发修改时只是一个近似值
+//	@receiver c
+//	@return int
+func (c *ConcurrentMap[K, V]) Len() int {
+	count := 0
+	for _, shard := range c.shards {
+		shard.RLock()
+		count += len(shard.items)
+		shard.RUnlock()
+	}
+	return count
+}
+
+// Keys
+//
+//	@Description: 所有key的快照
+//	@receiver c
+//	@return []K
+func (c *ConcurrentMap[K, V]) Keys() []K {
+	keys := make([]K, 0, c.Len())
+	for _, shard := range c.shards {
+		shard.RLock()
+		for k := range shard.items {
+			keys = append(keys, k)
+		}
+		shard.RUnlock()
+	}
+	return keys
+}
+
+// Values
+//
+//	@Description: 所有value的快照
+//	@receiver c
+//	@return []V
+func (c *ConcurrentMap[K, V]) Values() []V {
+	vals := make([]V, 0, c.Len())
+	for _, shard := range c.shards {
+		shard.RLock()
+		for _, v := range shard.items {
+			vals = append(vals, v)
+		}
+		shard.RUnlock()
+	}
+	return vals
+}
+
+// Items
+//
+//	@Description: 所有元素的快照，每个分片内部是一致的
+//	@receiver c
+//	@return map[K]V
+func (c *ConcurrentMap[K, V]) Items() map[K]V {
+	items := make(map[K]V, c.Len())
+	for _, shard := range c.shards {
+		shard.RLock()
+		for k, v := range shard.items {
+			items[k] = v
+		}
+		shard.RUnlock()
+	}
+	return items
+}
+
+// MSet
+//
+//	@Description: 批量设置，同一分片的key只加一次锁
+//	@receiver c
+//	@param items
+func (c *ConcurrentMap[K, V]) MSet(items map[K]V) {
+	groups := make(map[*ConcurrentMapShared[K, V]][]K)
+	for k := range items {
+		shard := c.GetShard(k)
+		groups[shard] = append(groups[shard], k)
+	}
+	for shard, keys := range groups {
+		shard.Lock()
+		for _, k := range keys {
+			shard.items[k] = items[k]
+		}
+		shard.Unlock()
+	}
+}
+
+// MGet
+//
+//	@Description: 批量获取，只返回存在的key
+//	@receiver c
+//	@param keys
+//	@return map[K]V
+func (c *ConcurrentMap[K, V]) MGet(keys ...K) map[K]V {
+	result := make(map[K]V, len(keys))
+	for _, k := range keys {
+		if v, ok := c.Get(k); ok {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Clear
+//
+//	@Description: 清空所有分片，原地删除以保留分片已经分配的容量
+//	@receiver c
+func (c *ConcurrentMap[K, V]) Clear() {
+	for _, shard := range c.shards {
+		shard.Lock()
+		for k := range shard.items {
+			delete(shard.items, k)
 		}
-		c2.Unlock()
+		shard.Unlock()
 	}
 }
 
+// MarshalJSON
+//
+//	@Description: 序列化为JSON对象
+//	@receiver c
+//	@return []byte
+//	@return error
+func (c *ConcurrentMap[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Items())
+}
+
+// UnmarshalJSON
+//
+//	@Description: 从JSON对象反序列化，零值的map会按ShardCount初始化分片
+//	@receiver c
+//	@param data
+//	@return error
+func (c *ConcurrentMap[K, V]) UnmarshalJSON(data []byte) error {
+	items := make(map[K]V)
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	if c.shards == nil {
+		*c = *NewConcurrentMap[K, V]()
+	}
+	c.MSet(items)
+	return nil
+}
+
 // Remove
 //
 //	@Description:
@@ -100,9 +345,139 @@ func (c *ConcurrentMap[K, V]) Remove(key K) {
 	shard.Unlock()
 }
 
+// GetOrSet
+//
+//	@Description: 如果key存在返回已有的值，否则设置为val并返回val
+//	@receiver c
+//	@param key
+//	@param val
+//	@return actual 最终存储在map中的值
+//	@return loaded key是否已经存在
+func (c *ConcurrentMap[K, V]) GetOrSet(key K, val V) (actual V, loaded bool) {
+	shard := c.GetShard(key)
+	shard.Lock()
+	defer shard.Unlock()
+	if old, ok := shard.items[key]; ok {
+		return old, true
+	}
+	shard.items[key] = val
+	return val, false
+}
+
+// SetIfAbsent
+//
+//	@Description: key不存在时才设置值
+//	@receiver c
+//	@param key
+//	@param val
+//	@return bool 是否设置成功
+func (c *ConcurrentMap[K, V]) SetIfAbsent(key K, val V) bool {
+	_, loaded := c.GetOrSet(key, val)
+	return !loaded
+}
+
+// Compute
+//
+//	@Description: 在分片锁内根据旧值计算新值，fx返回的keep为false时删除该key
+//	@receiver c
+//	@param key
+//	@param fx 参数old为旧值（不存在时为V的零值），exists表示key是否存在
+//	@return V 计算后的值
+//	@return bool 计算后key是否存在
+func (c *ConcurrentMap[K, V]) Compute(key K, fx func(old V, exists bool) (newVal V, keep bool)) (V, bool) {
+	shard := c.GetShard(key)
+	shard.Lock()
+	defer shard.Unlock()
+	old, exists := shard.items[key]
+	newVal, keep := fx(old, exists)
+	if !keep {
+		delete(shard.items, key)
+		var v V
+		return v, false
+	}
+	shard.items[key] = newVal
+	return newVal, true
+}
+
+// CompareAndSwap
+//
+//	@Description: 当key存在且当前值等于old时替换为new，V必须是可比较的类型，否则会panic（与sync.Map一致）
+//	@receiver c
+//	@param key
+//	@param old
+//	@param new
+//	@return swapped 是否替换成功
+func (c *ConcurrentMap[K, V]) CompareAndSwap(key K, old, new V) (swapped bool) {
+	shard := c.GetShard(key)
+	shard.Lock()
+	defer shard.Unlock()
+	cur, ok := shard.items[key]
+	if !ok || any(cur) != any(old) {
+		return false
+	}
+	shard.items[key] = new
+	return true
+}
+
+// CompareAndDelete
+//
+//	@Description: 当key存在且当前值等于old时删除，V必须是可比较的类型，否则会panic（与sync.Map一致）
+//	@receiver c
+//	@param key
+//	@param old
+//	@return deleted 是否删除成功
+func (c *ConcurrentMap[K, V]) CompareAndDelete(key K, old V) (deleted bool) {
+	shard := c.GetShard(key)
+	shard.Lock()
+	defer shard.Unlock()
+	cur, ok := shard.items[key]
+	if !ok || any(cur) != any(old) {
+		return false
+	}
+	delete(shard.items, key)
+	return true
+}
+
+// RemoveIf
+//
+//	@Description: key存在且fx返回true时删除
+//	@receiver c
+//	@param key
+//	@param fx
+//	@return bool 是否删除
+func (c *ConcurrentMap[K, V]) RemoveIf(key K, fx func(val V) bool) bool {
+	shard := c.GetShard(key)
+	shard.Lock()
+	defer shard.Unlock()
+	val, ok := shard.items[key]
+	if !ok || !fx(val) {
+		return false
+	}
+	delete(shard.items, key)
+	return true
+}
+
+// Pop
+//
+//	@Description: 删除key并返回被删除的值
+//	@receiver c
+//	@param key
+//	@return V 被删除的值，不存在时返回V的零值
+//	@return bool key是否存在
+func (c *ConcurrentMap[K, V]) Pop(key K) (V, bool) {
+	shard := c.GetShard(key)
+	shard.Lock()
+	defer shard.Unlock()
+	val, ok := shard.items[key]
+	if ok {
+		delete(shard.items, key)
+	}
+	return val, ok
+}
+
 // hash
 //
-//	@Description: 这里的hash方法是从一个帖子中找到的，测试过还不错
+//	@Description: 这里的hash方法是从一个帖子中找到的，测试过还不错，实现见Hasher
 //	@See https://blog.csdn.net/weixin_45583158/article/details/106894015
 //	@receiver c
 //	@Author yuhao <yuhao@mini1.cn>
@@ -110,26 +485,5 @@ func (c *ConcurrentMap[K, V]) Remove(key K) {
 //	@param key
 //	@return uintptr
 func (c *ConcurrentMap[K, V]) hash(key K) uintptr {
-	var m interface{} = make(map[K]struct{})
-	hf := (*mh)(*(*unsafe.Pointer)(unsafe.Pointer(&m))).hf
-	return hf(unsafe.Pointer(&key), 0)
-}
-
-// mh is an inlined combination of runtime._type and runtime.maptype
-type mh struct {
-	_  uintptr
-	_  uintptr
-	_  uint32
-	_  uint8
-	_  uint8
-	_  uint8
-	_  uint8
-	_  func(unsafe.Pointer, unsafe.Pointer) bool
-	_  *byte
-	_  int32
-	_  int32
-	_  unsafe.Pointer
-	_  unsafe.Pointer
-	_  unsafe.Pointer
-	hf func(unsafe.Pointer, uintptr) uintptr
+	return c.hasher.Hash(key)
 }
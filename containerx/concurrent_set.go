@@ -0,0 +1,161 @@
+package containerx
+
+// ConcurrentSet [K comparable]
+// @Description: 并发安全的集合，基于ConcurrentMap的分片实现
+type ConcurrentSet[K comparable] struct {
+	innerMap *ConcurrentMap[K, struct{}]
+}
+
+// NewConcurrentSet [K comparable]
+//
+//	@Description: 创建并发安全的集合，可选配置与ConcurrentMap相同
+//	@param opts
+//	@return *ConcurrentSet[K]
+func NewConcurrentSet[K comparable](opts ...ConcurrentMapOption) *ConcurrentSet[K] {
+	return &ConcurrentSet[K]{
+		innerMap: NewConcurrentMap[K, struct{}](opts...),
+	}
+}
+
+// NewConcurrentSetOf [K comparable]
+//
+//	@Description: 使用给定的元素创建并发安全的集合
+//	@param items
+//	@return *ConcurrentSet[K]
+func NewConcurrentSetOf[K comparable](items ...K) *ConcurrentSet[K] {
+	return NewConcurrentSet[K]().AddAll(&items)
+}
+
+// Add
+//
+//	@Description:
+//	@receiver r
+//	@param element
+//	@return *ConcurrentSet[K]
+func (r *ConcurrentSet[K]) Add(element K) *ConcurrentSet[K] {
+	r.innerMap.Set(element, struct{}{})
+	return r
+}
+
+// AddIfAbsent
+//
+//	@Description: 原子地添加元素
+//	@receiver r
+//	@param element
+//	@return bool 元素是否是新添加的
+func (r *ConcurrentSet[K]) AddIfAbsent(element K) bool {
+	return r.innerMap.SetIfAbsent(element, struct{}{})
+}
+
+// Size
+//
+//	@Description:
+//	@receiver r
+//	@return int
+func (r *ConcurrentSet[K]) Size() int {
+	return r.innerMap.Len()
+}
+
+// IsEmpty
+//
+//	@Description:
+//	@receiver r
+//	@return bool
+func (r *ConcurrentSet[K]) IsEmpty() bool {
+	return r.Size() == 0
+}
+
+// Remove
+//
+//	@Description:
+//	@receiver r
+//	@param element
+//	@return *ConcurrentSet[K]
+func (r *ConcurrentSet[K]) Remove(element K) *ConcurrentSet[K] {
+	r.innerMap.Remove(element)
+	return r
+}
+
+// ForEach
+//
+//	@Description: 遍历元素，回调时不持有锁
+//	@receiver r
+//	@param fx
+func (r *ConcurrentSet[K]) ForEach(fx func(item K)) {
+	r.innerMap.Each(func(key K, _ struct{}) {
+		fx(key)
+	})
+}
+
+// Contains
+//
+//	@Description:
+//	@receiver r
+//	@param element
+//	@return bool
+func (r *ConcurrentSet[K]) Contains(element K) bool {
+	_, ok := r.innerMap.Get(element)
+	return ok
+}
+
+// Clear
+//
+//	@Description:
+//	@receiver r
+//	@return *ConcurrentSet[K]
+func (r *ConcurrentSet[K]) Clear() *ConcurrentSet[K] {
+	r.innerMap.Clear()
+	return r
+}
+
+// Elements
+//
+//	@Description: 所有元素的快照
+//	@receiver r
+//	@return elements
+func (r *ConcurrentSet[K]) Elements() (elements []K) {
+	return r.innerMap.Keys()
+}
+
+// AddAll
+//
+//	@Description:
+//	@receiver r
+//	@param elements
+//	@return *ConcurrentSet[K]
+func (r *ConcurrentSet[K]) AddAll(elements *[]K) *ConcurrentSet[K] {
+	if elements != nil {
+		for _, k := range *elements {
+			r.Add(k)
+		}
+	}
+	return r
+}
+
+// Filter
+//
+//	@Description:
+//	@receiver r
+//	@param fx
+//	@return *ConcurrentSet[K]
+func (r *ConcurrentSet[K]) Filter(fx func(item K) bool) *ConcurrentSet[K] {
+	res := &ConcurrentSet[K]{
+		innerMap: NewConcurrentMapWithShards[K, struct{}](r.innerMap.ShardCount()),
+	}
+	r.ForEach(func(item K) {
+		if fx(item) {
+			res.Add(item)
+		}
+	})
+	return res
+}
+
+// ToSet
+//
+//	@Description: 拷贝成非并发安全的Set
+//	@receiver r
+//	@return *Set[K]
+func (r *ConcurrentSet[K]) ToSet() *Set[K] {
+	elements := r.Elements()
+	return NewSetOf(elements...)
+}
@@ -0,0 +1,197 @@
+package containerx
+
+const dequeMinCapacity = 8
+
+// Deque [T any]
+// @Description: 基于环形切片的双端队列，容量不足时自动扩容，非并发安全
+type Deque[T any] struct {
+	buf  []T
+	head int // 第一个元素的下标
+	size int
+}
+
+// NewDeque [T any]
+//
+//	@Description: 创建双端队列
+//	@param capacity 初始容量，可选
+//	@return *Deque[T]
+func NewDeque[T any](capacity ...int) *Deque[T] {
+	c := dequeMinCapacity
+	if len(capacity) > 0 && capacity[0] > c {
+		c = capacity[0]
+	}
+	return &Deque[T]{buf: make([]T, c)}
+}
+
+// Len
+//
+//	@Description:
+//	@receiver d
+//	@return int
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+// IsEmpty
+//
+//	@Description:
+//	@receiver d
+//	@return bool
+func (d *Deque[T]) IsEmpty() bool {
+	return d.size == 0
+}
+
+// PushFront
+//
+//	@Description: 在头部插入
+//	@receiver d
+//	@param v
+func (d *Deque[T]) PushFront(v T) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.buf)) % len(d.buf)
+	d.buf[d.head] = v
+	d.size++
+}
+
+// PushBack
+//
+//	@Description: 在尾部插入
+//	@receiver d
+//	@param v
+func (d *Deque[T]) PushBack(v T) {
+	d.grow()
+	d.buf[(d.head+d.size)%len(d.buf)] = v
+	d.size++
+}
+
+// PopFront
+//
+//	@Description: 取出头部元素
+//	@receiver d
+//	@return T
+//	@return bool 队列为空时为false
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	v := d.buf[d.head]
+	d.buf[d.head] = zero
+	d.head = (d.head + 1) % len(d.buf)
+	d.size--
+	return v, true
+}
+
+// PopBack
+//
+//	@Description: 取出尾部元素
+//	@receiver d
+//	@return T
+//	@return bool 队列为空时为false
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	i := (d.head + d.size - 1) % len(d.buf)
+	v := d.buf[i]
+	d.buf[i] = zero
+	d.size--
+	return v, true
+}
+
+// Front
+//
+//	@Description: 查看头部元素
+//	@receiver d
+//	@return T
+//	@return bool 队列为空时为false
+func (d *Deque[T]) Front() (T, bool) {
+	return d.At(0)
+}
+
+// Back
+//
+//	@Description: 查看尾部元素
+//	@receiver d
+//	@return T
+//	@return bool 队列为空时为false
+func (d *Deque[T]) Back() (T, bool) {
+	return d.At(d.size - 1)
+}
+
+// At
+//
+//	@Description: 按下标访问，0为头部
+//	@receiver d
+//	@param i
+//	@return T
+//	@return bool 下标越界时为false
+func (d *Deque[T]) At(i int) (T, bool) {
+	if i < 0 || i >= d.size {
+		var zero T
+		return zero, false
+	}
+	return d.buf[(d.head+i)%len(d.buf)], true
+}
+
+// Set
+//
+//	@Description: 按下标修改，0为头部
+//	@receiver d
+//	@param i
+//	@param v
+//	@return bool 下标越界时为false
+func (d *Deque[T]) Set(i int, v T) bool {
+	if i < 0 || i >= d.size {
+		return false
+	}
+	d.buf[(d.head+i)%len(d.buf)] = v
+	return true
+}
+
+// Values
+//
+//	@Description: 从头到尾返回所有元素
+//	@receiver d
+//	@return []T
+func (d *Deque[T]) Values() []T {
+	values := make([]T, d.size)
+	for i := 0; i < d.size; i++ {
+		values[i] = d.buf[(d.head+i)%len(d.buf)]
+	}
+	return values
+}
+
+// Clear
+//
+//	@Description:
+//	@receiver d
+func (d *Deque[T]) Clear() {
+	var zero T
+	for i := range d.buf {
+		d.buf[i] = zero
+	}
+	d.head = 0
+	d.size = 0
+}
+
+// grow
+//
+//	@Description: 满了时扩容为原来的两倍
+//	@receiver d
+func (d *Deque[T]) grow() {
+	if d.size < len(d.buf) {
+		return
+	}
+	newCap := len(d.buf) * 2
+	if newCap < dequeMinCapacity {
+		newCap = dequeMinCapacity
+	}
+	buf := make([]T, newCap)
+	for i := 0; i < d.size; i++ {
+		buf[i] = d.buf[(d.head+i)%len(d.buf)]
+	}
+	d.buf = buf
+	d.head = 0
+}
@@ -0,0 +1,49 @@
+package containerx
+
+import "unsafe"
+
+// Hasher [K comparable]
+// @Description: 借用runtime中map使用的hash函数计算任意comparable类型的hash值，
+// 同一个Hasher对相同的key总是返回相同的值，可以用于分片、分桶
+type Hasher[K comparable] struct {
+	hf func(unsafe.Pointer, uintptr) uintptr
+}
+
+// NewHasher [K comparable]
+//
+//	@Description: 创建hash函数，创建时从map[K]struct{}的类型信息中取出hash函数，之后计算时不再分配内存
+//	@See https://blog.csdn.net/weixin_45583158/article/details/106894015
+//	@return Hasher[K]
+func NewHasher[K comparable]() Hasher[K] {
+	var m interface{} = make(map[K]struct{})
+	return Hasher[K]{hf: (*mh)(*(*unsafe.Pointer)(unsafe.Pointer(&m))).hf}
+}
+
+// Hash
+//
+//	@Description: 计算key的hash值
+//	@receiver h
+//	@param key
+//	@return uintptr
+func (h Hasher[K]) Hash(key K) uintptr {
+	return h.hf(unsafe.Pointer(&key), 0)
+}
+
+// mh is an inlined combination of runtime._type and runtime.maptype
+type mh struct {
+	_  uintptr
+	_  uintptr
+	_  uint32
+	_  uint8
+	_  uint8
+	_  uint8
+	_  uint8
+	_  func(unsafe.Pointer, unsafe.Pointer) bool
+	_  *byte
+	_  int32
+	_  int32
+	_  unsafe.Pointer
+	_  unsafe.Pointer
+	_  unsafe.Pointer
+	hf func(unsafe.Pointer, uintptr) uintptr
+}
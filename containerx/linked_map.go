@@ -0,0 +1,266 @@
+package containerx
+
+type linkedMapEntry[K comparable, V any] struct {
+	key K
+	val V
+}
+
+// LinkedMap [K comparable, V any]
+// @Description: 保持插入顺序（或访问顺序）的map，非并发安全
+type LinkedMap[K comparable, V any] struct {
+	items       map[K]*Element[linkedMapEntry[K, V]]
+	list        List[linkedMapEntry[K, V]] // 头部是最早的元素，尾部是最新的元素
+	accessOrder bool                       // 为true时Get和Set会把元素移动到末尾
+}
+
+// NewLinkedMap [K comparable, V any]
+//
+//	@Description: 创建按插入顺序遍历的map
+//	@return *LinkedMap[K, V]
+func NewLinkedMap[K comparable, V any]() *LinkedMap[K, V] {
+	return &LinkedMap[K, V]{
+		items: map[K]*Element[linkedMapEntry[K, V]]{},
+	}
+}
+
+// NewAccessOrderLinkedMap [K comparable, V any]
+//
+//	@Description: 创建按访问顺序遍历的map，最近访问的元素在末尾，可以用来实现LRU
+//	@return *LinkedMap[K, V]
+func NewAccessOrderLinkedMap[K comparable, V any]() *LinkedMap[K, V] {
+	m := NewLinkedMap[K, V]()
+	m.accessOrder = true
+	return m
+}
+
+// Set
+//
+//	@Description: 设置值，如果key存在，返回旧的值 否则返回V的零值；插入顺序模式下更新已有key不改变位置
+//	@receiver m
+//	@param key
+//	@param val
+//	@return oldValue
+func (m *LinkedMap[K, V]) Set(key K, val V) (oldValue V) {
+	if node, ok := m.items[key]; ok {
+		oldValue = node.Value.val
+		node.Value.val = val
+		if m.accessOrder {
+			m.list.MoveToBack(node)
+		}
+		return oldValue
+	}
+	m.items[key] = m.list.PushBack(linkedMapEntry[K, V]{key: key, val: val})
+	return oldValue
+}
+
+// Get
+//
+//	@Description: 获取值，访问顺序模式下会把元素移动到末尾
+//	@receiver m
+//	@param key
+//	@param defaultVal
+//	@return V
+//	@return bool
+func (m *LinkedMap[K, V]) Get(key K, defaultVal ...V) (V, bool) {
+	node, ok := m.items[key]
+	if !ok {
+		if len(defaultVal) > 0 {
+			return defaultVal[0], ok
+		}
+		var v V
+		return v, ok
+	}
+	if m.accessOrder {
+		m.list.MoveToBack(node)
+	}
+	return node.Value.val, ok
+}
+
+// Peek
+//
+//	@Description: 获取值，不改变顺序
+//	@receiver m
+//	@param key
+//	@return V
+//	@return bool
+func (m *LinkedMap[K, V]) Peek(key K) (V, bool) {
+	node, ok := m.items[key]
+	if !ok {
+		var v V
+		return v, ok
+	}
+	return node.Value.val, ok
+}
+
+// Contains
+//
+//	@Description: 是否包含key，不改变顺序
+//	@receiver m
+//	@param key
+//	@return bool
+func (m *LinkedMap[K, V]) Contains(key K) bool {
+	_, ok := m.items[key]
+	return ok
+}
+
+// Remove
+//
+//	@Description: O(1)删除
+//	@receiver m
+//	@param key
+//	@return V 被删除的值
+//	@return bool key是否存在
+func (m *LinkedMap[K, V]) Remove(key K) (V, bool) {
+	node, ok := m.items[key]
+	if !ok {
+		var v V
+		return v, ok
+	}
+	delete(m.items, key)
+	return m.list.Remove(node).val, ok
+}
+
+// MoveToBack
+//
+//	@Description: 把key移动到末尾
+//	@receiver m
+//	@param key
+//	@return bool key是否存在
+func (m *LinkedMap[K, V]) MoveToBack(key K) bool {
+	node, ok := m.items[key]
+	if ok {
+		m.list.MoveToBack(node)
+	}
+	return ok
+}
+
+// MoveToFront
+//
+//	@Description: 把key移动到开头
+//	@receiver m
+//	@param key
+//	@return bool key是否存在
+func (m *LinkedMap[K, V]) MoveToFront(key K) bool {
+	node, ok := m.items[key]
+	if ok {
+		m.list.MoveToFront(node)
+	}
+	return ok
+}
+
+// Front
+//
+//	@Description: 最早的元素
+//	@receiver m
+//	@return key
+//	@return val
+//	@return ok map为空时为false
+func (m *LinkedMap[K, V]) Front() (key K, val V, ok bool) {
+	if m.Len() == 0 {
+		return key, val, false
+	}
+	front := m.list.Front().Value
+	return front.key, front.val, true
+}
+
+// Back
+//
+//	@Description: 最新的元素
+//	@receiver m
+//	@return key
+//	@return val
+//	@return ok map为空时为false
+func (m *LinkedMap[K, V]) Back() (key K, val V, ok bool) {
+	if m.Len() == 0 {
+		return key, val, false
+	}
+	back := m.list.Back().Value
+	return back.key, back.val, true
+}
+
+// PopFront
+//
+//	@Description: 删除并返回最早的元素
+//	@receiver m
+//	@return key
+//	@return val
+//	@return ok map为空时为false
+func (m *LinkedMap[K, V]) PopFront() (key K, val V, ok bool) {
+	if m.Len() == 0 {
+		return key, val, false
+	}
+	front := m.list.Remove(m.list.Front())
+	delete(m.items, front.key)
+	return front.key, front.val, true
+}
+
+// Len
+//
+//	@Description:
+//	@receiver m
+//	@return int
+func (m *LinkedMap[K, V]) Len() int {
+	return len(m.items)
+}
+
+// Keys
+//
+//	@Description: 按顺序返回所有key
+//	@receiver m
+//	@return []K
+func (m *LinkedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	for e := m.list.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.key)
+	}
+	return keys
+}
+
+// Values
+//
+//	@Description: 按顺序返回所有value
+//	@receiver m
+//	@return []V
+func (m *LinkedMap[K, V]) Values() []V {
+	vals := make([]V, 0, m.Len())
+	for e := m.list.Front(); e != nil; e = e.Next() {
+		vals = append(vals, e.Value.val)
+	}
+	return vals
+}
+
+// Each
+//
+//	@Description: 按顺序遍历
+//	@receiver m
+//	@param f
+func (m *LinkedMap[K, V]) Each(f func(key K, val V)) {
+	m.Range(func(key K, val V) bool {
+		f(key, val)
+		return true
+	})
+}
+
+// Range
+//
+//	@Description: 按顺序遍历，f返回false时停止；回调中可以删除当前元素
+//	@receiver m
+//	@param f
+func (m *LinkedMap[K, V]) Range(f func(key K, val V) bool) {
+	for e := m.list.Front(); e != nil; {
+		next := e.Next()
+		if !f(e.Value.key, e.Value.val) {
+			return
+		}
+		e = next
+	}
+}
+
+// Clear
+//
+//	@Description:
+//	@receiver m
+func (m *LinkedMap[K, V]) Clear() {
+	m.items = map[K]*Element[linkedMapEntry[K, V]]{}
+	m.list.Clear()
+}
@@ -0,0 +1,292 @@
+package containerx
+
+// Element [T any]
+// @Description: 双向链表的节点，同时作为O(1)移动、删除的句柄
+type Element[T any] struct {
+	Value      T
+	prev, next *Element[T]
+	list       *List[T]
+}
+
+// Next
+//
+//	@Description: 下一个节点
+//	@receiver e
+//	@return *Element[T] 没有时返回nil
+func (e *Element[T]) Next() *Element[T] {
+	if n := e.next; e.list != nil && n != &e.list.root {
+		return n
+	}
+	return nil
+}
+
+// Prev
+//
+//	@Description: 上一个节点
+//	@receiver e
+//	@return *Element[T] 没有时返回nil
+func (e *Element[T]) Prev() *Element[T] {
+	if p := e.prev; e.list != nil && p != &e.list.root {
+		return p
+	}
+	return nil
+}
+
+// List [T any]
+// @Description: 泛型双向链表，零值可以直接使用，非并发安全
+type List[T any] struct {
+	root Element[T] // 哨兵节点，root.next是头节点，root.prev是尾节点
+	len  int
+}
+
+// NewList [T any]
+//
+//	@Description: 创建双向链表
+//	@return *List[T]
+func NewList[T any]() *List[T] {
+	return new(List[T]).init()
+}
+
+// init
+//
+//	@Description: 初始化或清空链表
+//	@receiver l
+//	@return *List[T]
+func (l *List[T]) init() *List[T] {
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	l.len = 0
+	return l
+}
+
+// lazyInit
+//
+//	@Description: 零值的链表在第一次使用时初始化
+//	@receiver l
+func (l *List[T]) lazyInit() {
+	if l.root.next == nil {
+		l.init()
+	}
+}
+
+// Len
+//
+//	@Description:
+//	@receiver l
+//	@return int
+func (l *List[T]) Len() int {
+	return l.len
+}
+
+// Front
+//
+//	@Description: 头节点
+//	@receiver l
+//	@return *Element[T] 链表为空时返回nil
+func (l *List[T]) Front() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// Back
+//
+//	@Description: 尾节点
+//	@receiver l
+//	@return *Element[T] 链表为空时返回nil
+func (l *List[T]) Back() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// PushFront
+//
+//	@Description: 在头部插入
+//	@receiver l
+//	@param v
+//	@return *Element[T]
+func (l *List[T]) PushFront(v T) *Element[T] {
+	l.lazyInit()
+	return l.insert(&Element[T]{Value: v}, &l.root)
+}
+
+// PushBack
+//
+//	@Description: 在尾部插入
+//	@receiver l
+//	@param v
+//	@return *Element[T]
+func (l *List[T]) PushBack(v T) *Element[T] {
+	l.lazyInit()
+	return l.insert(&Element[T]{Value: v}, l.root.prev)
+}
+
+// InsertBefore
+//
+//	@Description: 在mark之前插入
+//	@receiver l
+//	@param v
+//	@param mark
+//	@return *Element[T] mark不属于当前链表时返回nil
+func (l *List[T]) InsertBefore(v T, mark *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&Element[T]{Value: v}, mark.prev)
+}
+
+// InsertAfter
+//
+//	@Description: 在mark之后插入
+//	@receiver l
+//	@param v
+//	@param mark
+//	@return *Element[T] mark不属于当前链表时返回nil
+func (l *List[T]) InsertAfter(v T, mark *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&Element[T]{Value: v}, mark)
+}
+
+// Remove
+//
+//	@Description: O(1)删除节点
+//	@receiver l
+//	@param e
+//	@return T 节点的值
+func (l *List[T]) Remove(e *Element[T]) T {
+	if e.list == l {
+		l.remove(e)
+	}
+	return e.Value
+}
+
+// MoveToFront
+//
+//	@Description: 把节点移动到头部
+//	@receiver l
+//	@param e
+func (l *List[T]) MoveToFront(e *Element[T]) {
+	if e.list != l || l.root.next == e {
+		return
+	}
+	l.move(e, &l.root)
+}
+
+// MoveToBack
+//
+//	@Description: 把节点移动到尾部
+//	@receiver l
+//	@param e
+func (l *List[T]) MoveToBack(e *Element[T]) {
+	if e.list != l || l.root.prev == e {
+		return
+	}
+	l.move(e, l.root.prev)
+}
+
+// MoveBefore
+//
+//	@Description: 把节点移动到mark之前
+//	@receiver l
+//	@param e
+//	@param mark
+func (l *List[T]) MoveBefore(e, mark *Element[T]) {
+	if e.list != l || mark.list != l || e == mark {
+		return
+	}
+	l.move(e, mark.prev)
+}
+
+// MoveAfter
+//
+//	@Description: 把节点移动到mark之后
+//	@receiver l
+//	@param e
+//	@param mark
+func (l *List[T]) MoveAfter(e, mark *Element[T]) {
+	if e.list != l || mark.list != l || e == mark {
+		return
+	}
+	l.move(e, mark)
+}
+
+// Values
+//
+//	@Description: 从头到尾返回所有值
+//	@receiver l
+//	@return []T
+func (l *List[T]) Values() []T {
+	values := make([]T, 0, l.len)
+	for e := l.Front(); e != nil; e = e.Next() {
+		values = append(values, e.Value)
+	}
+	return values
+}
+
+// Clear
+//
+//	@Description: 清空链表，已有的节点句柄全部失效
+//	@receiver l
+func (l *List[T]) Clear() {
+	for e := l.Front(); e != nil; {
+		next := e.Next()
+		e.prev, e.next, e.list = nil, nil, nil
+		e = next
+	}
+	l.init()
+}
+
+// insert
+//
+//	@Description: 把e插入到at之后
+//	@receiver l
+//	@param e
+//	@param at
+//	@return *Element[T]
+func (l *List[T]) insert(e, at *Element[T]) *Element[T] {
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+	e.list = l
+	l.len++
+	return e
+}
+
+// remove
+//
+//	@Description: 把e从链表中摘下
+//	@receiver l
+//	@param e
+func (l *List[T]) remove(e *Element[T]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.next = nil
+	e.prev = nil
+	e.list = nil
+	l.len--
+}
+
+// move
+//
+//	@Description: 把e移动到at之后
+//	@receiver l
+//	@param e
+//	@param at
+func (l *List[T]) move(e, at *Element[T]) {
+	if e == at {
+		return
+	}
+	e.prev.next = e.next
+	e.next.prev = e.prev
+
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+}
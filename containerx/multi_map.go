@@ -0,0 +1,213 @@
+package containerx
+
+// MultiMap [K comparable, V comparable]
+// @Description: 一个key对应多个value的map，同一个key下的value保持插入顺序且可以重复，非并发安全
+type MultiMap[K comparable, V comparable] struct {
+	innerMap map[K][]V
+	size     int // value的总个数
+}
+
+// NewMultiMap [K comparable, V comparable]
+//
+//	@Description:
+//	@return *MultiMap[K, V]
+func NewMultiMap[K comparable, V comparable]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		innerMap: make(map[K][]V, 8),
+	}
+}
+
+// Put
+//
+//	@Description: 给key追加一个value
+//	@receiver r
+//	@param key
+//	@param val
+//	@return *MultiMap[K, V]
+func (r *MultiMap[K, V]) Put(key K, val V) *MultiMap[K, V] {
+	r.innerMap[key] = append(r.innerMap[key], val)
+	r.size++
+	return r
+}
+
+// PutAll
+//
+//	@Description: 给key追加多个value
+//	@receiver r
+//	@param key
+//	@param vals
+//	@return *MultiMap[K, V]
+func (r *MultiMap[K, V]) PutAll(key K, vals ...V) *MultiMap[K, V] {
+	if len(vals) > 0 {
+		r.innerMap[key] = append(r.innerMap[key], vals...)
+		r.size += len(vals)
+	}
+	return r
+}
+
+// Get
+//
+//	@Description: key对应的所有value的拷贝
+//	@receiver r
+//	@param key
+//	@return []V 不存在时返回nil
+func (r *MultiMap[K, V]) Get(key K) []V {
+	vals, ok := r.innerMap[key]
+	if !ok {
+		return nil
+	}
+	res := make([]V, len(vals))
+	copy(res, vals)
+	return res
+}
+
+// Remove
+//
+//	@Description: 删除key下第一个等于val的value，key下没有value时删除key
+//	@receiver r
+//	@param key
+//	@param val
+//	@return bool 是否删除
+func (r *MultiMap[K, V]) Remove(key K, val V) bool {
+	vals := r.innerMap[key]
+	for i, v := range vals {
+		if v != val {
+			continue
+		}
+		if len(vals) == 1 {
+			delete(r.innerMap, key)
+		} else {
+			r.innerMap[key] = append(vals[:i:i], vals[i+1:]...)
+		}
+		r.size--
+		return true
+	}
+	return false
+}
+
+// RemoveAll
+//
+//	@Description: 删除key及其所有value
+//	@receiver r
+//	@param key
+//	@return []V 被删除的value
+func (r *MultiMap[K, V]) RemoveAll(key K) []V {
+	vals := r.innerMap[key]
+	delete(r.innerMap, key)
+	r.size -= len(vals)
+	return vals
+}
+
+// ContainsKey
+//
+//	@Description:
+//	@receiver r
+//	@param key
+//	@return bool
+func (r *MultiMap[K, V]) ContainsKey(key K) bool {
+	_, ok := r.innerMap[key]
+	return ok
+}
+
+// ContainsEntry
+//
+//	@Description: key下是否有等于val的value
+//	@receiver r
+//	@param key
+//	@param val
+//	@return bool
+func (r *MultiMap[K, V]) ContainsEntry(key K, val V) bool {
+	for _, v := range r.innerMap[key] {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+// Count
+//
+//	@Description: key下value的个数
+//	@receiver r
+//	@param key
+//	@return int
+func (r *MultiMap[K, V]) Count(key K) int {
+	return len(r.innerMap[key])
+}
+
+// Size
+//
+//	@Description: value的总个数
+//	@receiver r
+//	@return int
+func (r *MultiMap[K, V]) Size() int {
+	return r.size
+}
+
+// KeySize
+//
+//	@Description: key的个数
+//	@receiver r
+//	@return int
+func (r *MultiMap[K, V]) KeySize() int {
+	return len(r.innerMap)
+}
+
+// IsEmpty
+//
+//	@Description:
+//	@receiver r
+//	@return bool
+func (r *MultiMap[K, V]) IsEmpty() bool {
+	return r.size == 0
+}
+
+// Clear
+//
+//	@Description:
+//	@receiver r
+//	@return *MultiMap[K, V]
+func (r *MultiMap[K, V]) Clear() *MultiMap[K, V] {
+	r.innerMap = make(map[K][]V, 8)
+	r.size = 0
+	return r
+}
+
+// ForEach
+//
+//	@Description: 遍历每一个key-value对
+//	@receiver r
+//	@param fx
+func (r *MultiMap[K, V]) ForEach(fx func(key K, val V)) {
+	for k, vals := range r.innerMap {
+		for _, v := range vals {
+			fx(k, v)
+		}
+	}
+}
+
+// Keys
+//
+//	@Description:
+//	@receiver r
+//	@return []K
+func (r *MultiMap[K, V]) Keys() []K {
+	keys := make([]K, 0, len(r.innerMap))
+	for k := range r.innerMap {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values
+//
+//	@Description: 所有的value
+//	@receiver r
+//	@return []V
+func (r *MultiMap[K, V]) Values() []V {
+	vals := make([]V, 0, r.size)
+	for _, vs := range r.innerMap {
+		vals = append(vals, vs...)
+	}
+	return vals
+}
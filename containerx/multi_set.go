@@ -0,0 +1,240 @@
+package containerx
+
+// MultiSet [K comparable]
+// @Description: 可重复集合（bag），记录每个元素出现的次数，非并发安全
+type MultiSet[K comparable] struct {
+	innerMap map[K]int
+	size     int // 所有元素次数之和
+}
+
+// NewMultiSet [K comparable]
+//
+//	@Description:
+//	@return *MultiSet[K]
+func NewMultiSet[K comparable]() *MultiSet[K] {
+	return &MultiSet[K]{
+		innerMap: make(map[K]int, 8),
+	}
+}
+
+// NewMultiSetOf [K comparable]
+//
+//	@Description: 使用给定的元素创建，重复的元素会累加次数
+//	@param items
+//	@return *MultiSet[K]
+func NewMultiSetOf[K comparable](items ...K) *MultiSet[K] {
+	return NewMultiSet[K]().AddAll(&items)
+}
+
+// Add
+//
+//	@Description: 元素次数加1
+//	@receiver r
+//	@param element
+//	@return *MultiSet[K]
+func (r *MultiSet[K]) Add(element K) *MultiSet[K] {
+	return r.AddN(element, 1)
+}
+
+// AddN
+//
+//	@Description: 元素次数加n，n小于1时忽略
+//	@receiver r
+//	@param element
+//	@param n
+//	@return *MultiSet[K]
+func (r *MultiSet[K]) AddN(element K, n int) *MultiSet[K] {
+	if n > 0 {
+		r.innerMap[element] += n
+		r.size += n
+	}
+	return r
+}
+
+// AddAll
+//
+//	@Description:
+//	@receiver r
+//	@param elements
+//	@return *MultiSet[K]
+func (r *MultiSet[K]) AddAll(elements *[]K) *MultiSet[K] {
+	if elements != nil {
+		for _, k := range *elements {
+			r.Add(k)
+		}
+	}
+	return r
+}
+
+// Remove
+//
+//	@Description: 元素次数减1
+//	@receiver r
+//	@param element
+//	@return *MultiSet[K]
+func (r *MultiSet[K]) Remove(element K) *MultiSet[K] {
+	return r.RemoveN(element, 1)
+}
+
+// RemoveN
+//
+//	@Description: 元素次数减n，减到0时删除元素
+//	@receiver r
+//	@param element
+//	@param n
+//	@return *MultiSet[K]
+func (r *MultiSet[K]) RemoveN(element K, n int) *MultiSet[K] {
+	count, ok := r.innerMap[element]
+	if !ok || n < 1 {
+		return r
+	}
+	if n >= count {
+		delete(r.innerMap, element)
+		r.size -= count
+		return r
+	}
+	r.innerMap[element] = count - n
+	r.size -= n
+	return r
+}
+
+// RemoveAll
+//
+//	@Description: 删除元素的所有次数
+//	@receiver r
+//	@param element
+//	@return int 删除前的次数
+func (r *MultiSet[K]) RemoveAll(element K) int {
+	count := r.innerMap[element]
+	delete(r.innerMap, element)
+	r.size -= count
+	return count
+}
+
+// SetCount
+//
+//	@Description: 直接设置元素的次数，count小于1时删除元素
+//	@receiver r
+//	@param element
+//	@param count
+//	@return *MultiSet[K]
+func (r *MultiSet[K]) SetCount(element K, count int) *MultiSet[K] {
+	r.RemoveAll(element)
+	return r.AddN(element, count)
+}
+
+// Count
+//
+//	@Description: 元素出现的次数
+//	@receiver r
+//	@param element
+//	@return int
+func (r *MultiSet[K]) Count(element K) int {
+	return r.innerMap[element]
+}
+
+// Contains
+//
+//	@Description:
+//	@receiver r
+//	@param element
+//	@return bool
+func (r *MultiSet[K]) Contains(element K) bool {
+	_, ok := r.innerMap[element]
+	return ok
+}
+
+// Size
+//
+//	@Description: 所有元素次数之和
+//	@receiver r
+//	@return int
+func (r *MultiSet[K]) Size() int {
+	return r.size
+}
+
+// DistinctSize
+//
+//	@Description: 不同元素的个数
+//	@receiver r
+//	@return int
+func (r *MultiSet[K]) DistinctSize() int {
+	return len(r.innerMap)
+}
+
+// IsEmpty
+//
+//	@Description:
+//	@receiver r
+//	@return bool
+func (r *MultiSet[K]) IsEmpty() bool {
+	return r.size == 0
+}
+
+// Clear
+//
+//	@Description:
+//	@receiver r
+//	@return *MultiSet[K]
+func (r *MultiSet[K]) Clear() *MultiSet[K] {
+	r.innerMap = make(map[K]int, 8)
+	r.size = 0
+	return r
+}
+
+// ForEach
+//
+//	@Description: 遍历不同的元素及其次数
+//	@receiver r
+//	@param fx
+func (r *MultiSet[K]) ForEach(fx func(item K, count int)) {
+	for k, c := range r.innerMap {
+		fx(k, c)
+	}
+}
+
+// Elements
+//
+//	@Description: 所有元素，出现多次的元素会重复
+//	@receiver r
+//	@return elements
+func (r *MultiSet[K]) Elements() (elements []K) {
+	elements = make([]K, 0, r.size)
+	for k, c := range r.innerMap {
+		for i := 0; i < c; i++ {
+			elements = append(elements, k)
+		}
+	}
+	return elements
+}
+
+// ToSet
+//
+//	@Description: 不同的元素组成的集合
+//	@receiver r
+//	@return *Set[K]
+func (r *MultiSet[K]) ToSet() *Set[K] {
+	s := &Set[K]{
+		innerMap: make(map[K]struct{}, len(r.innerMap)),
+	}
+	for k := range r.innerMap {
+		s.innerMap[k] = struct{}{}
+	}
+	return s
+}
+
+// Filter
+//
+//	@Description:
+//	@receiver r
+//	@param fx
+//	@return *MultiSet[K]
+func (r *MultiSet[K]) Filter(fx func(item K, count int) bool) *MultiSet[K] {
+	res := NewMultiSet[K]()
+	for k, c := range r.innerMap {
+		if fx(k, c) {
+			res.AddN(k, c)
+		}
+	}
+	return res
+}
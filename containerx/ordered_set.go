@@ -0,0 +1,172 @@
+package containerx
+
+// OrderedSet [K comparable]
+// @Description: 按插入顺序（或访问顺序）遍历的集合，非并发安全
+type OrderedSet[K comparable] struct {
+	innerMap *LinkedMap[K, struct{}]
+}
+
+// NewOrderedSet [K comparable]
+//
+//	@Description: 创建按插入顺序遍历的集合
+//	@return *OrderedSet[K]
+func NewOrderedSet[K comparable]() *OrderedSet[K] {
+	return &OrderedSet[K]{
+		innerMap: NewLinkedMap[K, struct{}](),
+	}
+}
+
+// NewAccessOrderSet [K comparable]
+//
+//	@Description: 创建按访问顺序遍历的集合，重复Add已有元素会把它移动到末尾
+//	@return *OrderedSet[K]
+func NewAccessOrderSet[K comparable]() *OrderedSet[K] {
+	return &OrderedSet[K]{
+		innerMap: NewAccessOrderLinkedMap[K, struct{}](),
+	}
+}
+
+// NewOrderedSetOf [K comparable]
+//
+//	@Description: 使用给定的元素创建按插入顺序遍历的集合
+//	@param items
+//	@return *OrderedSet[K]
+func NewOrderedSetOf[K comparable](items ...K) *OrderedSet[K] {
+	return NewOrderedSet[K]().AddAll(&items)
+}
+
+// Add
+//
+//	@Description:
+//	@receiver r
+//	@param element
+//	@return *OrderedSet[K]
+func (r *OrderedSet[K]) Add(element K) *OrderedSet[K] {
+	r.innerMap.Set(element, struct{}{})
+	return r
+}
+
+// Size
+//
+//	@Description:
+//	@receiver r
+//	@return int
+func (r *OrderedSet[K]) Size() int {
+	return r.innerMap.Len()
+}
+
+// IsEmpty
+//
+//	@Description:
+//	@receiver r
+//	@return bool
+func (r *OrderedSet[K]) IsEmpty() bool {
+	return r.Size() == 0
+}
+
+// Remove
+//
+//	@Description: O(1)删除
+//	@receiver r
+//	@param element
+//	@return *OrderedSet[K]
+func (r *OrderedSet[K]) Remove(element K) *OrderedSet[K] {
+	r.innerMap.Remove(element)
+	return r
+}
+
+// ForEach
+//
+//	@Description: 按顺序遍历
+//	@receiver r
+//	@param fx
+func (r *OrderedSet[K]) ForEach(fx func(item K)) {
+	r.innerMap.Each(func(key K, _ struct{}) {
+		fx(key)
+	})
+}
+
+// Contains
+//
+//	@Description: 是否包含元素，不改变顺序
+//	@receiver r
+//	@param element
+//	@return bool
+func (r *OrderedSet[K]) Contains(element K) bool {
+	return r.innerMap.Contains(element)
+}
+
+// Clear
+//
+//	@Description:
+//	@receiver r
+//	@return *OrderedSet[K]
+func (r *OrderedSet[K]) Clear() *OrderedSet[K] {
+	r.innerMap.Clear()
+	return r
+}
+
+// Elements
+//
+//	@Description: 按顺序返回所有元素
+//	@receiver r
+//	@return elements
+func (r *OrderedSet[K]) Elements() (elements []K) {
+	return r.innerMap.Keys()
+}
+
+// AddAll
+//
+//	@Description:
+//	@receiver r
+//	@param elements
+//	@return *OrderedSet[K]
+func (r *OrderedSet[K]) AddAll(elements *[]K) *OrderedSet[K] {
+	if elements != nil {
+		for _, k := range *elements {
+			r.Add(k)
+		}
+	}
+	return r
+}
+
+// Filter
+//
+//	@Description: 保持原有顺序过滤
+//	@receiver r
+//	@param fx
+//	@return *OrderedSet[K]
+func (r *OrderedSet[K]) Filter(fx func(item K) bool) *OrderedSet[K] {
+	res := &OrderedSet[K]{
+		innerMap: NewLinkedMap[K, struct{}](),
+	}
+	res.innerMap.accessOrder = r.innerMap.accessOrder
+	r.ForEach(func(item K) {
+		if fx(item) {
+			res.Add(item)
+		}
+	})
+	return res
+}
+
+// First
+//
+//	@Description: 最早的元素
+//	@receiver r
+//	@return K
+//	@return bool 集合为空时为false
+func (r *OrderedSet[K]) First() (K, bool) {
+	k, _, ok := r.innerMap.Front()
+	return k, ok
+}
+
+// Last
+//
+//	@Description: 最新的元素
+//	@receiver r
+//	@return K
+//	@return bool 集合为空时为false
+func (r *OrderedSet[K]) Last() (K, bool) {
+	k, _, ok := r.innerMap.Back()
+	return k, ok
+}
@@ -0,0 +1,187 @@
+package containerx
+
+import "container/heap"
+
+// PriorityQueueItem [T any]
+// @Description: 优先队列中元素的句柄，用于Update、Remove、Fix
+type PriorityQueueItem[T any] struct {
+	Value T
+	index int // 在堆中的下标，不在队列中时为-1
+	queue *PriorityQueue[T]
+}
+
+// PriorityQueue [T any]
+// @Description: 基于container/heap的泛型优先队列，less(a, b)为true时a先出队，非并发安全
+type PriorityQueue[T any] struct {
+	h priorityHeap[T]
+}
+
+// priorityHeap 实现heap.Interface
+type priorityHeap[T any] struct {
+	items []*PriorityQueueItem[T]
+	less  func(a, b T) bool
+}
+
+func (h *priorityHeap[T]) Len() int {
+	return len(h.items)
+}
+
+func (h *priorityHeap[T]) Less(i, j int) bool {
+	return h.less(h.items[i].Value, h.items[j].Value)
+}
+
+func (h *priorityHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *priorityHeap[T]) Push(x any) {
+	item := x.(*PriorityQueueItem[T])
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *priorityHeap[T]) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items[n-1] = nil
+	h.items = h.items[:n-1]
+	item.index = -1
+	item.queue = nil
+	return item
+}
+
+// NewPriorityQueue [T any]
+//
+//	@Description: 创建优先队列
+//	@param less less(a, b)为true时a的优先级更高，传入a<b即为小顶堆
+//	@return *PriorityQueue[T]
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{h: priorityHeap[T]{less: less}}
+}
+
+// Push
+//
+//	@Description: 入队
+//	@receiver q
+//	@param val
+//	@return *PriorityQueueItem[T] 元素句柄
+func (q *PriorityQueue[T]) Push(val T) *PriorityQueueItem[T] {
+	item := &PriorityQueueItem[T]{Value: val, queue: q}
+	heap.Push(&q.h, item)
+	return item
+}
+
+// Pop
+//
+//	@Description: 取出优先级最高的元素
+//	@receiver q
+//	@return T
+//	@return bool 队列为空时为false
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	if len(q.h.items) == 0 {
+		var t T
+		return t, false
+	}
+	return heap.Pop(&q.h).(*PriorityQueueItem[T]).Value, true
+}
+
+// Peek
+//
+//	@Description: 查看优先级最高的元素，不出队
+//	@receiver q
+//	@return T
+//	@return bool 队列为空时为false
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if len(q.h.items) == 0 {
+		var t T
+		return t, false
+	}
+	return q.h.items[0].Value, true
+}
+
+// Update
+//
+//	@Description: 修改元素的值并调整位置
+//	@receiver q
+//	@param item
+//	@param val
+//	@return bool item不在当前队列中时为false
+func (q *PriorityQueue[T]) Update(item *PriorityQueueItem[T], val T) bool {
+	if !q.owns(item) {
+		return false
+	}
+	item.Value = val
+	heap.Fix(&q.h, item.index)
+	return true
+}
+
+// Fix
+//
+//	@Description: 元素的优先级在外部被修改后调整位置
+//	@receiver q
+//	@param item
+//	@return bool item不在当前队列中时为false
+func (q *PriorityQueue[T]) Fix(item *PriorityQueueItem[T]) bool {
+	if !q.owns(item) {
+		return false
+	}
+	heap.Fix(&q.h, item.index)
+	return true
+}
+
+// Remove
+//
+//	@Description: 删除指定元素
+//	@receiver q
+//	@param item
+//	@return T
+//	@return bool item不在当前队列中时为false
+func (q *PriorityQueue[T]) Remove(item *PriorityQueueItem[T]) (T, bool) {
+	if !q.owns(item) {
+		var t T
+		return t, false
+	}
+	return heap.Remove(&q.h, item.index).(*PriorityQueueItem[T]).Value, true
+}
+
+// Size
+//
+//	@Description:
+//	@receiver q
+//	@return int
+func (q *PriorityQueue[T]) Size() int {
+	return len(q.h.items)
+}
+
+// IsEmpty
+//
+//	@Description:
+//	@receiver q
+//	@return bool
+func (q *PriorityQueue[T]) IsEmpty() bool {
+	return len(q.h.items) == 0
+}
+
+// Clear
+//
+//	@Description: 清空队列，已有的句柄全部失效
+//	@receiver q
+func (q *PriorityQueue[T]) Clear() {
+	for _, item := range q.h.items {
+		item.index = -1
+		item.queue = nil
+	}
+	q.h.items = nil
+}
+
+// owns
+//
+//	@Description: item是否在当前队列中
+//	@receiver q
+//	@param item
+//	@return bool
+func (q *PriorityQueue[T]) owns(item *PriorityQueueItem[T]) bool {
+	return item != nil && item.queue == q && item.index >= 0
+}
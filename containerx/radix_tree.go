@@ -0,0 +1,365 @@
+package containerx
+
+import (
+	"sort"
+	"strings"
+)
+
+type radixLeaf[V any] struct {
+	key string
+	val V
+}
+
+type radixEdge[V any] struct {
+	label byte
+	node  *radixNode[V]
+}
+
+type radixNode[V any] struct {
+	prefix string
+	leaf   *radixLeaf[V]  // 不为nil时表示有key在这个节点结束
+	edges  []radixEdge[V] // 按label排序
+}
+
+// RadixTree [K ~string | ~[]byte, V any]
+// @Description: 基数树（压缩前缀树），支持string和[]byte类型的key，按key的字典序遍历，非并发安全
+type RadixTree[K ~string | ~[]byte, V any] struct {
+	root *radixNode[V]
+	size int
+}
+
+// NewRadixTree [K ~string | ~[]byte, V any]
+//
+//	@Description: 创建基数树
+//	@return *RadixTree[K, V]
+func NewRadixTree[K ~string | ~[]byte, V any]() *RadixTree[K, V] {
+	return &RadixTree[K, V]{root: &radixNode[V]{}}
+}
+
+// Len
+//
+//	@Description: key的个数
+//	@receiver t
+//	@return int
+func (t *RadixTree[K, V]) Len() int {
+	return t.size
+}
+
+// Insert
+//
+//	@Description: 插入或更新
+//	@receiver t
+//	@param key
+//	@param val
+//	@return oldValue key已存在时返回旧的值 否则返回V的零值
+//	@return updated key是否已存在
+func (t *RadixTree[K, V]) Insert(key K, val V) (oldValue V, updated bool) {
+	s := string(key)
+	var parent *radixNode[V]
+	n := t.root
+	search := s
+	for {
+		if len(search) == 0 {
+			if n.leaf != nil {
+				oldValue = n.leaf.val
+				n.leaf.val = val
+				return oldValue, true
+			}
+			n.leaf = &radixLeaf[V]{key: s, val: val}
+			t.size++
+			return oldValue, false
+		}
+
+		parent = n
+		n = n.getEdge(search[0])
+		if n == nil {
+			parent.addEdge(radixEdge[V]{
+				label: search[0],
+				node:  &radixNode[V]{prefix: search, leaf: &radixLeaf[V]{key: s, val: val}},
+			})
+			t.size++
+			return oldValue, false
+		}
+
+		common := commonPrefixLen(search, n.prefix)
+		if common == len(n.prefix) {
+			search = search[common:]
+			continue
+		}
+
+		// 分裂节点：公共前缀作为新的中间节点
+		child := &radixNode[V]{prefix: search[:common]}
+		parent.replaceEdge(search[0], child)
+		n.prefix = n.prefix[common:]
+		child.addEdge(radixEdge[V]{label: n.prefix[0], node: n})
+
+		leaf := &radixLeaf[V]{key: s, val: val}
+		search = search[common:]
+		if len(search) == 0 {
+			child.leaf = leaf
+		} else {
+			child.addEdge(radixEdge[V]{
+				label: search[0],
+				node:  &radixNode[V]{prefix: search, leaf: leaf},
+			})
+		}
+		t.size++
+		return oldValue, false
+	}
+}
+
+// Get
+//
+//	@Description:
+//	@receiver t
+//	@param key
+//	@return V
+//	@return bool
+func (t *RadixTree[K, V]) Get(key K) (V, bool) {
+	n := t.root
+	search := string(key)
+	for {
+		if len(search) == 0 {
+			if n.leaf != nil {
+				return n.leaf.val, true
+			}
+			break
+		}
+		n = n.getEdge(search[0])
+		if n == nil || !strings.HasPrefix(search, n.prefix) {
+			break
+		}
+		search = search[len(n.prefix):]
+	}
+	var v V
+	return v, false
+}
+
+// Delete
+//
+//	@Description: 删除key，并合并只剩一个子节点的节点
+//	@receiver t
+//	@param key
+//	@return V 被删除的值
+//	@return bool key是否存在
+func (t *RadixTree[K, V]) Delete(key K) (V, bool) {
+	var parent *radixNode[V]
+	var label byte
+	n := t.root
+	search := string(key)
+	for len(search) > 0 {
+		parent = n
+		label = search[0]
+		n = n.getEdge(label)
+		if n == nil || !strings.HasPrefix(search, n.prefix) {
+			var v V
+			return v, false
+		}
+		search = search[len(n.prefix):]
+	}
+	if n.leaf == nil {
+		var v V
+		return v, false
+	}
+
+	leaf := n.leaf
+	n.leaf = nil
+	t.size--
+	if parent != nil && len(n.edges) == 0 {
+		parent.delEdge(label)
+	}
+	if n != t.root && len(n.edges) == 1 {
+		n.mergeChild()
+	}
+	if parent != nil && parent != t.root && len(parent.edges) == 1 && parent.leaf == nil {
+		parent.mergeChild()
+	}
+	return leaf.val, true
+}
+
+// LongestPrefix
+//
+//	@Description: 查找是key前缀的最长的已有key，可用于路由匹配
+//	@receiver t
+//	@param key
+//	@return K 匹配到的key
+//	@return V
+//	@return bool 没有匹配时为false
+func (t *RadixTree[K, V]) LongestPrefix(key K) (K, V, bool) {
+	var last *radixLeaf[V]
+	n := t.root
+	search := string(key)
+	for {
+		if n.leaf != nil {
+			last = n.leaf
+		}
+		if len(search) == 0 {
+			break
+		}
+		n = n.getEdge(search[0])
+		if n == nil || !strings.HasPrefix(search, n.prefix) {
+			break
+		}
+		search = search[len(n.prefix):]
+	}
+	if last == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return K(last.key), last.val, true
+}
+
+// WalkPrefix
+//
+//	@Description: 按字典序遍历以prefix开头的key，f返回false时停止
+//	@receiver t
+//	@param prefix
+//	@param f
+func (t *RadixTree[K, V]) WalkPrefix(prefix K, f func(key K, val V) bool) {
+	n := t.root
+	search := string(prefix)
+	for {
+		if len(search) == 0 {
+			t.walk(n, f)
+			return
+		}
+		n = n.getEdge(search[0])
+		if n == nil {
+			return
+		}
+		if strings.HasPrefix(search, n.prefix) {
+			search = search[len(n.prefix):]
+			continue
+		}
+		if strings.HasPrefix(n.prefix, search) {
+			t.walk(n, f)
+		}
+		return
+	}
+}
+
+// Walk
+//
+//	@Description: 按字典序遍历所有key，f返回false时停止
+//	@receiver t
+//	@param f
+func (t *RadixTree[K, V]) Walk(f func(key K, val V) bool) {
+	t.walk(t.root, f)
+}
+
+// Keys
+//
+//	@Description: 按字典序返回所有key
+//	@receiver t
+//	@return []K
+func (t *RadixTree[K, V]) Keys() []K {
+	keys := make([]K, 0, t.size)
+	t.Walk(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// walk
+//
+//	@Description: 先序遍历，返回false表示已被f中止
+//	@receiver t
+//	@param n
+//	@param f
+//	@return bool
+func (t *RadixTree[K, V]) walk(n *radixNode[V], f func(key K, val V) bool) bool {
+	if n.leaf != nil && !f(K(n.leaf.key), n.leaf.val) {
+		return false
+	}
+	for _, e := range n.edges {
+		if !t.walk(e.node, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// getEdge
+//
+//	@Description: 二分查找label对应的子节点
+//	@receiver n
+//	@param label
+//	@return *radixNode[V] 不存在时返回nil
+func (n *radixNode[V]) getEdge(label byte) *radixNode[V] {
+	i := sort.Search(len(n.edges), func(i int) bool { return n.edges[i].label >= label })
+	if i < len(n.edges) && n.edges[i].label == label {
+		return n.edges[i].node
+	}
+	return nil
+}
+
+// addEdge
+//
+//	@Description: 按label顺序插入子节点
+//	@receiver n
+//	@param e
+func (n *radixNode[V]) addEdge(e radixEdge[V]) {
+	i := sort.Search(len(n.edges), func(i int) bool { return n.edges[i].label >= e.label })
+	n.edges = append(n.edges, radixEdge[V]{})
+	copy(n.edges[i+1:], n.edges[i:])
+	n.edges[i] = e
+}
+
+// replaceEdge
+//
+//	@Description: 替换label对应的子节点
+//	@receiver n
+//	@param label
+//	@param node
+func (n *radixNode[V]) replaceEdge(label byte, node *radixNode[V]) {
+	i := sort.Search(len(n.edges), func(i int) bool { return n.edges[i].label >= label })
+	if i < len(n.edges) && n.edges[i].label == label {
+		n.edges[i].node = node
+	}
+}
+
+// delEdge
+//
+//	@Description: 删除label对应的子节点
+//	@receiver n
+//	@param label
+func (n *radixNode[V]) delEdge(label byte) {
+	i := sort.Search(len(n.edges), func(i int) bool { return n.edges[i].label >= label })
+	if i < len(n.edges) && n.edges[i].label == label {
+		copy(n.edges[i:], n.edges[i+1:])
+		n.edges[len(n.edges)-1] = radixEdge[V]{}
+		n.edges = n.edges[:len(n.edges)-1]
+	}
+}
+
+// mergeChild
+//
+//	@Description: 把唯一的子节点合并到当前节点
+//	@receiver n
+func (n *radixNode[V]) mergeChild() {
+	child := n.edges[0].node
+	n.prefix += child.prefix
+	n.leaf = child.leaf
+	n.edges = child.edges
+}
+
+// commonPrefixLen
+//
+//	@Description: 公共前缀的长度
+//	@param a
+//	@param b
+//	@return int
+func commonPrefixLen(a, b string) int {
+	l := len(a)
+	if len(b) < l {
+		l = len(b)
+	}
+	for i := 0; i < l; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return l
+}
@@ -1,5 +1,7 @@
 package containerx
 
+import "encoding/json"
+
 // Set [K comparable]
 // @Description:
 type Set[K comparable] struct {
@@ -16,6 +18,21 @@ func NewSet[K comparable]() *Set[K] {
 	}
 }
 
+// NewSetOf [K comparable]
+//
+//	@Description: 使用给定的元素创建集合
+//	@param items
+//	@return *Set[K]
+func NewSetOf[K comparable](items ...K) *Set[K] {
+	s := &Set[K]{
+		innerMap: make(map[K]struct{}, len(items)),
+	}
+	for _, item := range items {
+		s.Add(item)
+	}
+	return s
+}
+
 // Add
 //
 //	@Description:
@@ -132,3 +149,182 @@ func (r *Set[K]) Filter(fx func(item K) bool) *Set[K] {
 	}
 	return NewSet[K]().AddAll(&elements)
 }
+
+// Clone
+//
+//	@Description: 浅拷贝一个新的集合
+//	@receiver r
+//	@return *Set[K]
+func (r *Set[K]) Clone() *Set[K] {
+	s := &Set[K]{
+		innerMap: make(map[K]struct{}, len(r.innerMap)),
+	}
+	for k := range r.innerMap {
+		s.innerMap[k] = struct{}{}
+	}
+	return s
+}
+
+// Union
+//
+//	@Description: 并集，返回新的集合，以较大的集合为基础拷贝
+//	@receiver r
+//	@param other
+//	@return *Set[K]
+func (r *Set[K]) Union(other *Set[K]) *Set[K] {
+	big, small := r, other
+	if small.Size() > big.Size() {
+		big, small = small, big
+	}
+	res := big.Clone()
+	for k := range small.innerMap {
+		res.innerMap[k] = struct{}{}
+	}
+	return res
+}
+
+// Intersection
+//
+//	@Description: 交集，返回新的集合，遍历较小的集合
+//	@receiver r
+//	@param other
+//	@return *Set[K]
+func (r *Set[K]) Intersection(other *Set[K]) *Set[K] {
+	big, small := r, other
+	if small.Size() > big.Size() {
+		big, small = small, big
+	}
+	res := NewSet[K]()
+	for k := range small.innerMap {
+		if big.Contains(k) {
+			res.innerMap[k] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Difference
+//
+//	@Description: 差集，返回在r中但不在other中的元素
+//	@receiver r
+//	@param other
+//	@return *Set[K]
+func (r *Set[K]) Difference(other *Set[K]) *Set[K] {
+	res := NewSet[K]()
+	for k := range r.innerMap {
+		if !other.Contains(k) {
+			res.innerMap[k] = struct{}{}
+		}
+	}
+	return res
+}
+
+// SymmetricDifference
+//
+//	@Description: 对称差集，返回只在其中一个集合中出现的元素
+//	@receiver r
+//	@param other
+//	@return *Set[K]
+func (r *Set[K]) SymmetricDifference(other *Set[K]) *Set[K] {
+	res := r.Difference(other)
+	for k := range other.innerMap {
+		if !r.Contains(k) {
+			res.innerMap[k] = struct{}{}
+		}
+	}
+	return res
+}
+
+// IsSubset
+//
+//	@Description: r是否是other的子集
+//	@receiver r
+//	@param other
+//	@return bool
+func (r *Set[K]) IsSubset(other *Set[K]) bool {
+	if r.Size() > other.Size() {
+		return false
+	}
+	for k := range r.innerMap {
+		if !other.Contains(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset
+//
+//	@Description: r是否是other的超集
+//	@receiver r
+//	@param other
+//	@return bool
+func (r *Set[K]) IsSuperset(other *Set[K]) bool {
+	return other.IsSubset(r)
+}
+
+// Equal
+//
+//	@Description: 两个集合的元素是否完全相同
+//	@receiver r
+//	@param other
+//	@return bool
+func (r *Set[K]) Equal(other *Set[K]) bool {
+	return r.Size() == other.Size() && r.IsSubset(other)
+}
+
+// Map
+//
+//	@Description: 对每个元素执行fx，结果组成新的集合；需要转换成其他类型时使用MapSet
+//	@receiver r
+//	@param fx
+//	@return *Set[K]
+func (r *Set[K]) Map(fx func(item K) K) *Set[K] {
+	return MapSet(r, fx)
+}
+
+// MapSet [K, R comparable]
+//
+//	@Description: 对每个元素执行fx，结果组成新的集合
+//	@param s
+//	@param fx
+//	@return *Set[R]
+func MapSet[K, R comparable](s *Set[K], fx func(item K) R) *Set[R] {
+	res := &Set[R]{
+		innerMap: make(map[R]struct{}, s.Size()),
+	}
+	for k := range s.innerMap {
+		res.innerMap[fx(k)] = struct{}{}
+	}
+	return res
+}
+
+// MarshalJSON
+//
+//	@Description: 序列化为JSON数组
+//	@receiver r
+//	@return []byte
+//	@return error
+func (r *Set[K]) MarshalJSON() ([]byte, error) {
+	elements := r.Elements()
+	if elements == nil {
+		elements = []K{}
+	}
+	return json.Marshal(elements)
+}
+
+// UnmarshalJSON
+//
+//	@Description: 从JSON数组反序列化，会覆盖原有的元素
+//	@receiver r
+//	@param data
+//	@return error
+func (r *Set[K]) UnmarshalJSON(data []byte) error {
+	var elements []K
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	r.innerMap = make(map[K]struct{}, len(elements))
+	r.AddAll(&elements)
+	return nil
+}
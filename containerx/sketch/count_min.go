@@ -0,0 +1,270 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// CountMinOption 创建CountMinSketch时的可选配置
+type CountMinOption func(s *CountMinSketch)
+
+// WithConservativeUpdate
+//
+//	@Description: 使用保守更新，只增加等于当前最小值的计数器，可以明显降低高估
+//	@return CountMinOption
+func WithConservativeUpdate() CountMinOption {
+	return func(s *CountMinSketch) {
+		s.conservative = true
+	}
+}
+
+// CountMinSketch 频率估算，估算值不会小于真实值，非并发安全
+type CountMinSketch struct {
+	width        uint64
+	depth        uint64
+	total        uint64
+	conservative bool
+	counters     []uint64 // depth行width列
+}
+
+// NewCountMinSketch
+//
+//	@Description: 创建CountMinSketch
+//	@param width 每行的计数器个数，小于1时按1处理
+//	@param depth 行数（hash函数个数），小于1时按1处理
+//	@param opts
+//	@return *CountMinSketch
+func NewCountMinSketch(width, depth uint, opts ...CountMinOption) *CountMinSketch {
+	if width < 1 {
+		width = 1
+	}
+	if depth < 1 {
+		depth = 1
+	}
+	s := &CountMinSketch{
+		width:    uint64(width),
+		depth:    uint64(depth),
+		counters: make([]uint64, width*depth),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// NewCountMinSketchWithEstimates
+//
+//	@Description: 根据误差参数创建CountMinSketch，估算值以1-delta的概率不超过 真实值+epsilon*总数
+//	@param epsilon 相对误差，范围(0, 1)
+//	@param delta 失败概率，范围(0, 1)
+//	@param opts
+//	@return *CountMinSketch
+func NewCountMinSketchWithEstimates(epsilon, delta float64, opts ...CountMinOption) *CountMinSketch {
+	if epsilon <= 0 || epsilon >= 1 {
+		epsilon = 0.001
+	}
+	if delta <= 0 || delta >= 1 {
+		delta = 0.01
+	}
+	width := uint(math.Ceil(math.E / epsilon))
+	depth := uint(math.Ceil(math.Log(1 / delta)))
+	return NewCountMinSketch(width, depth, opts...)
+}
+
+// Width
+//
+//	@Description: 每行的计数器个数
+//	@receiver s
+//	@return uint
+func (s *CountMinSketch) Width() uint {
+	return uint(s.width)
+}
+
+// Depth
+//
+//	@Description: 行数
+//	@receiver s
+//	@return uint
+func (s *CountMinSketch) Depth() uint {
+	return uint(s.depth)
+}
+
+// Total
+//
+//	@Description: 所有Add的count之和
+//	@receiver s
+//	@return uint64
+func (s *CountMinSketch) Total() uint64 {
+	return s.total
+}
+
+// Add
+//
+//	@Description: 增加元素的计数
+//	@receiver s
+//	@param data
+//	@param count
+//	@return uint64 增加后的估算值
+func (s *CountMinSketch) Add(data []byte, count uint64) uint64 {
+	s.total += count
+	h1, h2 := hash128(data)
+	if !s.conservative {
+		estimate := uint64(math.MaxUint64)
+		for i := uint64(0); i < s.depth; i++ {
+			idx := s.index(h1, h2, i)
+			s.counters[idx] += count
+			if s.counters[idx] < estimate {
+				estimate = s.counters[idx]
+			}
+		}
+		return estimate
+	}
+	// 保守更新：新的估算值为 旧的最小值+count，只把小于它的计数器提升到该值
+	estimate := s.estimate(h1, h2) + count
+	for i := uint64(0); i < s.depth; i++ {
+		idx := s.index(h1, h2, i)
+		if s.counters[idx] < estimate {
+			s.counters[idx] = estimate
+		}
+	}
+	return estimate
+}
+
+// AddString
+//
+//	@Description: 增加字符串元素的计数
+//	@receiver s
+//	@param item
+//	@param count
+//	@return uint64 增加后的估算值
+func (s *CountMinSketch) AddString(item string, count uint64) uint64 {
+	return s.Add([]byte(item), count)
+}
+
+// Estimate
+//
+//	@Description: 估算元素的计数
+//	@receiver s
+//	@param data
+//	@return uint64
+func (s *CountMinSketch) Estimate(data []byte) uint64 {
+	h1, h2 := hash128(data)
+	return s.estimate(h1, h2)
+}
+
+// EstimateString
+//
+//	@Description: 估算字符串元素的计数
+//	@receiver s
+//	@param item
+//	@return uint64
+func (s *CountMinSketch) EstimateString(item string) uint64 {
+	return s.Estimate([]byte(item))
+}
+
+// Merge
+//
+//	@Description: 合并另一个参数相同的CountMinSketch，对应计数器相加
+//	@receiver s
+//	@param other
+//	@return error 参数不同时返回ErrIncompatible
+func (s *CountMinSketch) Merge(other *CountMinSketch) error {
+	if s.width != other.width || s.depth != other.depth {
+		return ErrIncompatible
+	}
+	for i, c := range other.counters {
+		s.counters[i] += c
+	}
+	s.total += other.total
+	return nil
+}
+
+// Clear
+//
+//	@Description: 清空
+//	@receiver s
+//	@return *CountMinSketch
+func (s *CountMinSketch) Clear() *CountMinSketch {
+	for i := range s.counters {
+		s.counters[i] = 0
+	}
+	s.total = 0
+	return s
+}
+
+// MarshalBinary
+//
+//	@Description: 序列化，格式为大端序的 width(uint64) depth(uint64) total(uint64) conservative(uint8) 计数器([]uint64)
+//	@receiver s
+//	@return []byte
+//	@return error
+func (s *CountMinSketch) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 25+8*len(s.counters))
+	binary.BigEndian.PutUint64(data[0:], s.width)
+	binary.BigEndian.PutUint64(data[8:], s.depth)
+	binary.BigEndian.PutUint64(data[16:], s.total)
+	if s.conservative {
+		data[24] = 1
+	}
+	for i, c := range s.counters {
+		binary.BigEndian.PutUint64(data[25+8*i:], c)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary
+//
+//	@Description: 反序列化
+//	@receiver s
+//	@param data
+//	@return error
+func (s *CountMinSketch) UnmarshalBinary(data []byte) error {
+	if len(data) < 25 {
+		return ErrInvalidData
+	}
+	width := binary.BigEndian.Uint64(data[0:])
+	depth := binary.BigEndian.Uint64(data[8:])
+	// 用除法校验长度，width*depth*8可能溢出
+	payload := uint64(len(data) - 25)
+	if width == 0 || depth == 0 || width > math.MaxUint32 || depth > math.MaxUint32 ||
+		payload%8 != 0 || width > payload/8/depth || width*depth != payload/8 {
+		return ErrInvalidData
+	}
+	s.width, s.depth = width, depth
+	s.total = binary.BigEndian.Uint64(data[16:])
+	s.conservative = data[24] == 1
+	s.counters = make([]uint64, width*depth)
+	for i := range s.counters {
+		s.counters[i] = binary.BigEndian.Uint64(data[25+8*i:])
+	}
+	return nil
+}
+
+// index
+//
+//	@Description: 第row行对应的计数器下标
+//	@receiver s
+//	@param h1
+//	@param h2
+//	@param row
+//	@return uint64
+func (s *CountMinSketch) index(h1, h2, row uint64) uint64 {
+	return row*s.width + (h1+row*h2)%s.width
+}
+
+// estimate
+//
+//	@Description: 各行计数器的最小值
+//	@receiver s
+//	@param h1
+//	@param h2
+//	@return uint64
+func (s *CountMinSketch) estimate(h1, h2 uint64) uint64 {
+	estimate := uint64(math.MaxUint64)
+	for i := uint64(0); i < s.depth; i++ {
+		if c := s.counters[s.index(h1, h2, i)]; c < estimate {
+			estimate = c
+		}
+	}
+	return estimate
+}
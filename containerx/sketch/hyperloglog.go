@@ -0,0 +1,218 @@
+// Package sketch
+// @Description: 基数统计和频率统计的概率数据结构，均支持序列化与合并，方便汇总多个节点的数据
+package sketch
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+const (
+	MinPrecision = 4
+	MaxPrecision = 18
+)
+
+var (
+	ErrInvalidPrecision = errors.New("sketch: precision out of range [4, 18]")
+	ErrIncompatible     = errors.New("sketch: sketches have different parameters")
+	ErrInvalidData      = errors.New("sketch: invalid binary data")
+)
+
+// HyperLogLog 基数估算，使用2^p个6位寄存器（按字节存储），标准误差约为1.04/sqrt(2^p)，非并发安全
+type HyperLogLog struct {
+	p         uint8
+	registers []uint8
+}
+
+// NewHyperLogLog
+//
+//	@Description: 创建HyperLogLog
+//	@param precision 精度p，范围[4, 18]，寄存器个数为2^p
+//	@return *HyperLogLog
+//	@return error
+func NewHyperLogLog(precision uint8) (*HyperLogLog, error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, ErrInvalidPrecision
+	}
+	return &HyperLogLog{
+		p:         precision,
+		registers: make([]uint8, 1<<precision),
+	}, nil
+}
+
+// Precision
+//
+//	@Description: 精度p
+//	@receiver h
+//	@return uint8
+func (h *HyperLogLog) Precision() uint8 {
+	return h.p
+}
+
+// Add
+//
+//	@Description: 添加元素
+//	@receiver h
+//	@param data
+//	@return *HyperLogLog
+func (h *HyperLogLog) Add(data []byte) *HyperLogLog {
+	x := hash64(data)
+	idx := x >> (64 - h.p)
+	// 剩余的位左移后在最低的有效位补1，保证前导零个数不超过64-p
+	w := x<<h.p | 1<<(h.p-1)
+	rho := uint8(bits.LeadingZeros64(w)) + 1
+	if rho > h.registers[idx] {
+		h.registers[idx] = rho
+	}
+	return h
+}
+
+// AddString
+//
+//	@Description: 添加字符串元素
+//	@receiver h
+//	@param s
+//	@return *HyperLogLog
+func (h *HyperLogLog) AddString(s string) *HyperLogLog {
+	return h.Add([]byte(s))
+}
+
+// Count
+//
+//	@Description: 估算不同元素的个数
+//	@receiver h
+//	@return uint64
+func (h *HyperLogLog) Count() uint64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := hllAlpha(len(h.registers)) * m * m / sum
+	// 小基数时使用线性计数修正
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// Merge
+//
+//	@Description: 合并另一个精度相同的HyperLogLog，结果为两者的并集
+//	@receiver h
+//	@param other
+//	@return error 精度不同时返回ErrIncompatible
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.p != other.p {
+		return ErrIncompatible
+	}
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+	return nil
+}
+
+// Clear
+//
+//	@Description: 清空
+//	@receiver h
+//	@return *HyperLogLog
+func (h *HyperLogLog) Clear() *HyperLogLog {
+	for i := range h.registers {
+		h.registers[i] = 0
+	}
+	return h
+}
+
+// MarshalBinary
+//
+//	@Description: 序列化，格式为 p(uint8) 寄存器([]uint8)
+//	@receiver h
+//	@return []byte
+//	@return error
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 1+len(h.registers))
+	data[0] = h.p
+	copy(data[1:], h.registers)
+	return data, nil
+}
+
+// UnmarshalBinary
+//
+//	@Description: 反序列化
+//	@receiver h
+//	@param data
+//	@return error
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 || data[0] < MinPrecision || data[0] > MaxPrecision || len(data)-1 != 1<<data[0] {
+		return ErrInvalidData
+	}
+	// 寄存器记录的是前导零个数加1，不会超过64-p+1
+	maxRank := 64 - data[0] + 1
+	for _, r := range data[1:] {
+		if r > maxRank {
+			return ErrInvalidData
+		}
+	}
+	h.p = data[0]
+	h.registers = make([]uint8, len(data)-1)
+	copy(h.registers, data[1:])
+	return nil
+}
+
+// hllAlpha
+//
+//	@Description: 偏差修正系数
+//	@param m 寄存器个数
+//	@return float64
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
+
+// hash64
+//
+//	@Description: FNV-1a后再用murmur3的fmix64打散，保证高位分布均匀
+//	@param data
+//	@return uint64
+func hash64(data []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// hash128
+//
+//	@Description: 两个相互独立的64位hash，用于组合出多个hash函数
+//	@param data
+//	@return h1
+//	@return h2
+func hash128(data []byte) (h1, h2 uint64) {
+	h := fnv.New128a()
+	_, _ = h.Write(data)
+	sum := h.Sum(nil)
+	return binary.BigEndian.Uint64(sum[0:8]), binary.BigEndian.Uint64(sum[8:16])
+}
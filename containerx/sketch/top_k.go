@@ -0,0 +1,222 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"math"
+	"sort"
+)
+
+// HeavyHitter 出现次数最多的元素及其估算次数
+type HeavyHitter struct {
+	Item  string
+	Count uint64
+}
+
+// TopK 基于CountMinSketch和小顶堆统计出现次数最多的k个元素，非并发安全
+type TopK struct {
+	k      int
+	sketch *CountMinSketch
+	heap   *containerx.PriorityQueue[*HeavyHitter] // 堆顶是当前候选中次数最少的
+	items  map[string]*containerx.PriorityQueueItem[*HeavyHitter]
+}
+
+// NewTopK
+//
+//	@Description: 创建TopK
+//	@param k 统计的元素个数
+//	@param sketch 用于计数的CountMinSketch，建议开启保守更新
+//	@return *TopK
+func NewTopK(k int, sketch *CountMinSketch) *TopK {
+	if k < 1 {
+		k = 1
+	}
+	return &TopK{
+		k:      k,
+		sketch: sketch,
+		heap:   newHeavyHitterHeap(),
+		items:  make(map[string]*containerx.PriorityQueueItem[*HeavyHitter], k),
+	}
+}
+
+// newHeavyHitterHeap
+//
+//	@Description: 按次数排序的小顶堆，次数相同时按元素排序，保证结果确定
+//	@return *containerx.PriorityQueue[*HeavyHitter]
+func newHeavyHitterHeap() *containerx.PriorityQueue[*HeavyHitter] {
+	return containerx.NewPriorityQueue(func(a, b *HeavyHitter) bool {
+		if a.Count != b.Count {
+			return a.Count < b.Count
+		}
+		return a.Item > b.Item
+	})
+}
+
+// Sketch
+//
+//	@Description: 底层的CountMinSketch
+//	@receiver t
+//	@return *CountMinSketch
+func (t *TopK) Sketch() *CountMinSketch {
+	return t.sketch
+}
+
+// Add
+//
+//	@Description: 增加元素的计数并更新候选
+//	@receiver t
+//	@param item
+//	@param count
+//	@return uint64 增加后的估算值
+func (t *TopK) Add(item string, count uint64) uint64 {
+	estimate := t.sketch.AddString(item, count)
+	t.offer(item, estimate)
+	return estimate
+}
+
+// offer
+//
+//	@Description: 用新的估算值更新候选
+//	@receiver t
+//	@param item
+//	@param estimate
+func (t *TopK) offer(item string, estimate uint64) {
+	if handle, ok := t.items[item]; ok {
+		handle.Value.Count = estimate
+		t.heap.Fix(handle)
+		return
+	}
+	if t.heap.Size() >= t.k {
+		smallest, _ := t.heap.Peek()
+		if estimate <= smallest.Count {
+			return
+		}
+		t.heap.Pop()
+		delete(t.items, smallest.Item)
+	}
+	t.items[item] = t.heap.Push(&HeavyHitter{Item: item, Count: estimate})
+}
+
+// List
+//
+//	@Description: 按次数从多到少返回候选
+//	@receiver t
+//	@return []HeavyHitter
+func (t *TopK) List() []HeavyHitter {
+	res := make([]HeavyHitter, 0, len(t.items))
+	for _, handle := range t.items {
+		res = append(res, *handle.Value)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Item < res[j].Item
+	})
+	return res
+}
+
+// Merge
+//
+//	@Description: 合并另一个节点的TopK，用合并后的计数重新评估两边的候选
+//	@receiver t
+//	@param other
+//	@return error CountMinSketch参数不同时返回ErrIncompatible
+func (t *TopK) Merge(other *TopK) error {
+	if err := t.sketch.Merge(other.sketch); err != nil {
+		return err
+	}
+	candidates := make([]string, 0, len(t.items)+len(other.items))
+	for item := range t.items {
+		candidates = append(candidates, item)
+	}
+	for item := range other.items {
+		if _, ok := t.items[item]; !ok {
+			candidates = append(candidates, item)
+		}
+	}
+	t.rebuild(candidates)
+	return nil
+}
+
+// rebuild
+//
+//	@Description: 清空候选后按当前计数重新加入
+//	@receiver t
+//	@param candidates
+func (t *TopK) rebuild(candidates []string) {
+	t.heap.Clear()
+	t.items = make(map[string]*containerx.PriorityQueueItem[*HeavyHitter], t.k)
+	for _, item := range candidates {
+		t.offer(item, t.sketch.EstimateString(item))
+	}
+}
+
+// MarshalBinary
+//
+//	@Description: 序列化，格式为大端序的 k(uint32) sketch长度(uint32) sketch 候选个数(uint32) [长度(uint32) 候选]...
+//	@receiver t
+//	@return []byte
+//	@return error
+func (t *TopK) MarshalBinary() ([]byte, error) {
+	sketchData, err := t.sketch.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 8, 12+len(sketchData))
+	binary.BigEndian.PutUint32(data[0:], uint32(t.k))
+	binary.BigEndian.PutUint32(data[4:], uint32(len(sketchData)))
+	data = append(data, sketchData...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(t.items)))
+	for item := range t.items {
+		data = binary.BigEndian.AppendUint32(data, uint32(len(item)))
+		data = append(data, item...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary
+//
+//	@Description: 反序列化，候选的次数由sketch重新估算
+//	@receiver t
+//	@param data
+//	@return error
+func (t *TopK) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return ErrInvalidData
+	}
+	k := binary.BigEndian.Uint32(data[0:])
+	sketchLen := uint64(binary.BigEndian.Uint32(data[4:]))
+	data = data[8:]
+	if k == 0 || k > math.MaxInt32 || uint64(len(data)) < sketchLen+4 {
+		return ErrInvalidData
+	}
+	sketch := &CountMinSketch{}
+	if err := sketch.UnmarshalBinary(data[:sketchLen]); err != nil {
+		return err
+	}
+	data = data[sketchLen:]
+	n := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint64(n)*4 > uint64(len(data)) {
+		return ErrInvalidData
+	}
+	candidates := make([]string, 0, n)
+	for i := uint32(0); i < n; i++ {
+		if len(data) < 4 {
+			return ErrInvalidData
+		}
+		l := uint64(binary.BigEndian.Uint32(data))
+		data = data[4:]
+		if uint64(len(data)) < l {
+			return ErrInvalidData
+		}
+		candidates = append(candidates, string(data[:l]))
+		data = data[l:]
+	}
+	t.k = int(k)
+	t.sketch = sketch
+	t.heap = newHeavyHitterHeap()
+	t.rebuild(candidates)
+	return nil
+}
@@ -0,0 +1,477 @@
+package containerx
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	skipListMaxLevel = 32
+	skipListP        = 0.25
+)
+
+// Ordered 支持 < > 比较的类型，与algorithm/sort.Compare类似，额外支持string和自定义的底层类型
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// CompareOrdered [K Ordered]
+//
+//	@Description: Ordered类型的默认比较函数
+//	@param a
+//	@param b
+//	@return int a<b返回-1，a>b返回1，相等返回0
+func CompareOrdered[K Ordered](a, b K) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+type skipListLevel[K any, V any] struct {
+	next *skipListNode[K, V]
+	span int // 到next之间跨过的节点数，用于计算排名
+}
+
+type skipListNode[K any, V any] struct {
+	key    K
+	val    V
+	prev   *skipListNode[K, V]
+	levels []skipListLevel[K, V]
+}
+
+// SortedMap [K any, V any]
+// @Description: 按key有序的map，基于带跨度的跳表实现，支持范围查询和排名，非并发安全
+type SortedMap[K any, V any] struct {
+	compare func(a, b K) int
+	header  *skipListNode[K, V]
+	tail    *skipListNode[K, V]
+	level   int
+	length  int
+	rand    *rand.Rand
+}
+
+// NewSortedMap [K Ordered, V any]
+//
+//	@Description: 创建按key自然顺序排序的map
+//	@return *SortedMap[K, V]
+func NewSortedMap[K Ordered, V any]() *SortedMap[K, V] {
+	return NewSortedMapFunc[K, V](CompareOrdered[K])
+}
+
+// NewSortedMapFunc [K any, V any]
+//
+//	@Description: 使用自定义比较函数创建有序map
+//	@param compare a<b返回负数，a>b返回正数，相等返回0
+//	@return *SortedMap[K, V]
+func NewSortedMapFunc[K any, V any](compare func(a, b K) int) *SortedMap[K, V] {
+	return &SortedMap[K, V]{
+		compare: compare,
+		header:  &skipListNode[K, V]{levels: make([]skipListLevel[K, V], skipListMaxLevel)},
+		level:   1,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// randomLevel
+//
+//	@Description: 随机生成新节点的层数
+//	@receiver m
+//	@return int
+func (m *SortedMap[K, V]) randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && m.rand.Float64() < skipListP {
+		level++
+	}
+	return level
+}
+
+// Set
+//
+//	@Description: 设置值，如果key存在，返回旧的值 否则返回V的零值
+//	@receiver m
+//	@param key
+//	@param val
+//	@return oldValue
+func (m *SortedMap[K, V]) Set(key K, val V) (oldValue V) {
+	var update [skipListMaxLevel]*skipListNode[K, V]
+	var rank [skipListMaxLevel]int
+	x := m.header
+	for i := m.level - 1; i >= 0; i-- {
+		if i < m.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].next != nil && m.compare(x.levels[i].next.key, key) < 0 {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+	if next := x.levels[0].next; next != nil && m.compare(next.key, key) == 0 {
+		oldValue = next.val
+		next.val = val
+		return oldValue
+	}
+
+	level := m.randomLevel()
+	if level > m.level {
+		for i := m.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = m.header
+			update[i].levels[i].span = m.length
+		}
+		m.level = level
+	}
+	node := &skipListNode[K, V]{key: key, val: val, levels: make([]skipListLevel[K, V], level)}
+	for i := 0; i < level; i++ {
+		node.levels[i].next = update[i].levels[i].next
+		update[i].levels[i].next = node
+		node.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+	for i := level; i < m.level; i++ {
+		update[i].levels[i].span++
+	}
+	if update[0] != m.header {
+		node.prev = update[0]
+	}
+	if node.levels[0].next != nil {
+		node.levels[0].next.prev = node
+	} else {
+		m.tail = node
+	}
+	m.length++
+	return oldValue
+}
+
+// Get
+//
+//	@Description:
+//	@receiver m
+//	@param key
+//	@param defaultVal
+//	@return V
+//	@return bool
+func (m *SortedMap[K, V]) Get(key K, defaultVal ...V) (V, bool) {
+	node := m.ceilingNode(key)
+	if node == nil || m.compare(node.key, key) != 0 {
+		if len(defaultVal) > 0 {
+			return defaultVal[0], false
+		}
+		var v V
+		return v, false
+	}
+	return node.val, true
+}
+
+// Contains
+//
+//	@Description:
+//	@receiver m
+//	@param key
+//	@return bool
+func (m *SortedMap[K, V]) Contains(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Remove
+//
+//	@Description:
+//	@receiver m
+//	@param key
+//	@return V 被删除的值
+//	@return bool key是否存在
+func (m *SortedMap[K, V]) Remove(key K) (V, bool) {
+	var update [skipListMaxLevel]*skipListNode[K, V]
+	x := m.header
+	for i := m.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && m.compare(x.levels[i].next.key, key) < 0 {
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+	x = x.levels[0].next
+	if x == nil || m.compare(x.key, key) != 0 {
+		var v V
+		return v, false
+	}
+	m.removeNode(x, &update)
+	return x.val, true
+}
+
+// removeNode
+//
+//	@Description: 删除节点，update是每一层中x的前驱
+//	@receiver m
+//	@param x
+//	@param update
+func (m *SortedMap[K, V]) removeNode(x *skipListNode[K, V], update *[skipListMaxLevel]*skipListNode[K, V]) {
+	for i := 0; i < m.level; i++ {
+		if update[i].levels[i].next == x {
+			update[i].levels[i].span += x.levels[i].span - 1
+			update[i].levels[i].next = x.levels[i].next
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+	if x.levels[0].next != nil {
+		x.levels[0].next.prev = x.prev
+	} else {
+		m.tail = x.prev
+	}
+	for m.level > 1 && m.header.levels[m.level-1].next == nil {
+		m.level--
+	}
+	m.length--
+}
+
+// Len
+//
+//	@Description:
+//	@receiver m
+//	@return int
+func (m *SortedMap[K, V]) Len() int {
+	return m.length
+}
+
+// Clear
+//
+//	@Description:
+//	@receiver m
+func (m *SortedMap[K, V]) Clear() {
+	m.header = &skipListNode[K, V]{levels: make([]skipListLevel[K, V], skipListMaxLevel)}
+	m.tail = nil
+	m.level = 1
+	m.length = 0
+}
+
+// Min
+//
+//	@Description: 最小的key
+//	@receiver m
+//	@return key
+//	@return val
+//	@return ok map为空时为false
+func (m *SortedMap[K, V]) Min() (key K, val V, ok bool) {
+	return nodeEntry(m.header.levels[0].next)
+}
+
+// Max
+//
+//	@Description: 最大的key
+//	@receiver m
+//	@return key
+//	@return val
+//	@return ok map为空时为false
+func (m *SortedMap[K, V]) Max() (key K, val V, ok bool) {
+	return nodeEntry(m.tail)
+}
+
+// Floor
+//
+//	@Description: 小于等于key的最大元素
+//	@receiver m
+//	@param key
+//	@return K
+//	@return V
+//	@return bool 不存在时为false
+func (m *SortedMap[K, V]) Floor(key K) (K, V, bool) {
+	return nodeEntry(m.lastNodeBefore(key, true))
+}
+
+// Lower
+//
+//	@Description: 小于key的最大元素
+//	@receiver m
+//	@param key
+//	@return K
+//	@return V
+//	@return bool 不存在时为false
+func (m *SortedMap[K, V]) Lower(key K) (K, V, bool) {
+	return nodeEntry(m.lastNodeBefore(key, false))
+}
+
+// Ceiling
+//
+//	@Description: 大于等于key的最小元素
+//	@receiver m
+//	@param key
+//	@return K
+//	@return V
+//	@return bool 不存在时为false
+func (m *SortedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	return nodeEntry(m.ceilingNode(key))
+}
+
+// Higher
+//
+//	@Description: 大于key的最小元素
+//	@receiver m
+//	@param key
+//	@return K
+//	@return V
+//	@return bool 不存在时为false
+func (m *SortedMap[K, V]) Higher(key K) (K, V, bool) {
+	x := m.lastNodeBefore(key, true)
+	if x == nil {
+		return nodeEntry(m.header.levels[0].next)
+	}
+	return nodeEntry(x.levels[0].next)
+}
+
+// Rank
+//
+//	@Description: 小于key的元素个数，key存在时即为它从0开始的排名
+//	@receiver m
+//	@param key
+//	@return int
+func (m *SortedMap[K, V]) Rank(key K) int {
+	rank := 0
+	x := m.header
+	for i := m.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && m.compare(x.levels[i].next.key, key) < 0 {
+			rank += x.levels[i].span
+			x = x.levels[i].next
+		}
+	}
+	return rank
+}
+
+// Select
+//
+//	@Description: 按排名获取元素
+//	@receiver m
+//	@param rank 从0开始的排名
+//	@return K
+//	@return V
+//	@return bool 排名越界时为false
+func (m *SortedMap[K, V]) Select(rank int) (K, V, bool) {
+	if rank < 0 || rank >= m.length {
+		return nodeEntry[K, V](nil)
+	}
+	target := rank + 1
+	traversed := 0
+	x := m.header
+	for i := m.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && traversed+x.levels[i].span <= target {
+			traversed += x.levels[i].span
+			x = x.levels[i].next
+		}
+		if traversed == target {
+			return nodeEntry(x)
+		}
+	}
+	return nodeEntry[K, V](nil)
+}
+
+// Range
+//
+//	@Description: 按顺序遍历[from, to)区间内的元素，f返回false时停止
+//	@receiver m
+//	@param from 包含
+//	@param to 不包含
+//	@param f
+func (m *SortedMap[K, V]) Range(from, to K, f func(key K, val V) bool) {
+	for x := m.ceilingNode(from); x != nil && m.compare(x.key, to) < 0; {
+		next := x.levels[0].next
+		if !f(x.key, x.val) {
+			return
+		}
+		x = next
+	}
+}
+
+// Each
+//
+//	@Description: 按key从小到大遍历
+//	@receiver m
+//	@param f
+func (m *SortedMap[K, V]) Each(f func(key K, val V)) {
+	for x := m.header.levels[0].next; x != nil; x = x.levels[0].next {
+		f(x.key, x.val)
+	}
+}
+
+// Keys
+//
+//	@Description: 从小到大返回所有key
+//	@receiver m
+//	@return []K
+func (m *SortedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.length)
+	for x := m.header.levels[0].next; x != nil; x = x.levels[0].next {
+		keys = append(keys, x.key)
+	}
+	return keys
+}
+
+// Values
+//
+//	@Description: 按key从小到大返回所有value
+//	@receiver m
+//	@return []V
+func (m *SortedMap[K, V]) Values() []V {
+	vals := make([]V, 0, m.length)
+	for x := m.header.levels[0].next; x != nil; x = x.levels[0].next {
+		vals = append(vals, x.val)
+	}
+	return vals
+}
+
+// lastNodeBefore
+//
+//	@Description: 查找最后一个小于（inclusive为true时小于等于）key的节点
+//	@receiver m
+//	@param key
+//	@param inclusive
+//	@return *skipListNode[K, V] 不存在时为nil
+func (m *SortedMap[K, V]) lastNodeBefore(key K, inclusive bool) *skipListNode[K, V] {
+	x := m.header
+	for i := m.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil {
+			c := m.compare(x.levels[i].next.key, key)
+			if c > 0 || (c == 0 && !inclusive) {
+				break
+			}
+			x = x.levels[i].next
+		}
+	}
+	if x == m.header {
+		return nil
+	}
+	return x
+}
+
+// ceilingNode
+//
+//	@Description: 查找第一个大于等于key的节点
+//	@receiver m
+//	@param key
+//	@return *skipListNode[K, V] 不存在时为nil
+func (m *SortedMap[K, V]) ceilingNode(key K) *skipListNode[K, V] {
+	x := m.lastNodeBefore(key, false)
+	if x == nil {
+		return m.header.levels[0].next
+	}
+	return x.levels[0].next
+}
+
+// nodeEntry [K any, V any]
+//
+//	@Description: 拆出节点中的key和value
+//	@param x
+//	@return key
+//	@return val
+//	@return ok x为nil时为false
+func nodeEntry[K any, V any](x *skipListNode[K, V]) (key K, val V, ok bool) {
+	if x == nil {
+		return key, val, false
+	}
+	return x.key, x.val, true
+}
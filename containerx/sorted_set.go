@@ -0,0 +1,251 @@
+package containerx
+
+// SortedSet [K any]
+// @Description: 有序集合，基于SortedMap实现，非并发安全
+type SortedSet[K any] struct {
+	innerMap *SortedMap[K, struct{}]
+}
+
+// NewSortedSet [K Ordered]
+//
+//	@Description: 创建按自然顺序排序的集合
+//	@return *SortedSet[K]
+func NewSortedSet[K Ordered]() *SortedSet[K] {
+	return NewSortedSetFunc[K](CompareOrdered[K])
+}
+
+// NewSortedSetFunc [K any]
+//
+//	@Description: 使用自定义比较函数创建有序集合
+//	@param compare a<b返回负数，a>b返回正数，相等返回0
+//	@return *SortedSet[K]
+func NewSortedSetFunc[K any](compare func(a, b K) int) *SortedSet[K] {
+	return &SortedSet[K]{
+		innerMap: NewSortedMapFunc[K, struct{}](compare),
+	}
+}
+
+// NewSortedSetOf [K Ordered]
+//
+//	@Description: 使用给定的元素创建按自然顺序排序的集合
+//	@param items
+//	@return *SortedSet[K]
+func NewSortedSetOf[K Ordered](items ...K) *SortedSet[K] {
+	return NewSortedSet[K]().AddAll(&items)
+}
+
+// Add
+//
+//	@Description:
+//	@receiver r
+//	@param element
+//	@return *SortedSet[K]
+func (r *SortedSet[K]) Add(element K) *SortedSet[K] {
+	r.innerMap.Set(element, struct{}{})
+	return r
+}
+
+// AddAll
+//
+//	@Description:
+//	@receiver r
+//	@param elements
+//	@return *SortedSet[K]
+func (r *SortedSet[K]) AddAll(elements *[]K) *SortedSet[K] {
+	if elements != nil {
+		for _, k := range *elements {
+			r.Add(k)
+		}
+	}
+	return r
+}
+
+// Size
+//
+//	@Description:
+//	@receiver r
+//	@return int
+func (r *SortedSet[K]) Size() int {
+	return r.innerMap.Len()
+}
+
+// IsEmpty
+//
+//	@Description:
+//	@receiver r
+//	@return bool
+func (r *SortedSet[K]) IsEmpty() bool {
+	return r.Size() == 0
+}
+
+// Remove
+//
+//	@Description:
+//	@receiver r
+//	@param element
+//	@return *SortedSet[K]
+func (r *SortedSet[K]) Remove(element K) *SortedSet[K] {
+	r.innerMap.Remove(element)
+	return r
+}
+
+// Contains
+//
+//	@Description:
+//	@receiver r
+//	@param element
+//	@return bool
+func (r *SortedSet[K]) Contains(element K) bool {
+	return r.innerMap.Contains(element)
+}
+
+// Clear
+//
+//	@Description:
+//	@receiver r
+//	@return *SortedSet[K]
+func (r *SortedSet[K]) Clear() *SortedSet[K] {
+	r.innerMap.Clear()
+	return r
+}
+
+// ForEach
+//
+//	@Description: 从小到大遍历
+//	@receiver r
+//	@param fx
+func (r *SortedSet[K]) ForEach(fx func(item K)) {
+	r.innerMap.Each(func(key K, _ struct{}) {
+		fx(key)
+	})
+}
+
+// Elements
+//
+//	@Description: 从小到大返回所有元素
+//	@receiver r
+//	@return elements
+func (r *SortedSet[K]) Elements() (elements []K) {
+	return r.innerMap.Keys()
+}
+
+// Filter
+//
+//	@Description:
+//	@receiver r
+//	@param fx
+//	@return *SortedSet[K]
+func (r *SortedSet[K]) Filter(fx func(item K) bool) *SortedSet[K] {
+	res := NewSortedSetFunc[K](r.innerMap.compare)
+	r.ForEach(func(item K) {
+		if fx(item) {
+			res.Add(item)
+		}
+	})
+	return res
+}
+
+// Min
+//
+//	@Description: 最小的元素
+//	@receiver r
+//	@return K
+//	@return bool 集合为空时为false
+func (r *SortedSet[K]) Min() (K, bool) {
+	k, _, ok := r.innerMap.Min()
+	return k, ok
+}
+
+// Max
+//
+//	@Description: 最大的元素
+//	@receiver r
+//	@return K
+//	@return bool 集合为空时为false
+func (r *SortedSet[K]) Max() (K, bool) {
+	k, _, ok := r.innerMap.Max()
+	return k, ok
+}
+
+// Floor
+//
+//	@Description: 小于等于element的最大元素
+//	@receiver r
+//	@param element
+//	@return K
+//	@return bool
+func (r *SortedSet[K]) Floor(element K) (K, bool) {
+	k, _, ok := r.innerMap.Floor(element)
+	return k, ok
+}
+
+// Lower
+//
+//	@Description: 小于element的最大元素
+//	@receiver r
+//	@param element
+//	@return K
+//	@return bool
+func (r *SortedSet[K]) Lower(element K) (K, bool) {
+	k, _, ok := r.innerMap.Lower(element)
+	return k, ok
+}
+
+// Ceiling
+//
+//	@Description: 大于等于element的最小元素
+//	@receiver r
+//	@param element
+//	@return K
+//	@return bool
+func (r *SortedSet[K]) Ceiling(element K) (K, bool) {
+	k, _, ok := r.innerMap.Ceiling(element)
+	return k, ok
+}
+
+// Higher
+//
+//	@Description: 大于element的最小元素
+//	@receiver r
+//	@param element
+//	@return K
+//	@return bool
+func (r *SortedSet[K]) Higher(element K) (K, bool) {
+	k, _, ok := r.innerMap.Higher(element)
+	return k, ok
+}
+
+// Range
+//
+//	@Description: 从小到大遍历[from, to)区间内的元素，fx返回false时停止
+//	@receiver r
+//	@param from 包含
+//	@param to 不包含
+//	@param fx
+func (r *SortedSet[K]) Range(from, to K, fx func(item K) bool) {
+	r.innerMap.Range(from, to, func(key K, _ struct{}) bool {
+		return fx(key)
+	})
+}
+
+// Rank
+//
+//	@Description: 小于element的元素个数
+//	@receiver r
+//	@param element
+//	@return int
+func (r *SortedSet[K]) Rank(element K) int {
+	return r.innerMap.Rank(element)
+}
+
+// Select
+//
+//	@Description: 按从0开始的排名获取元素
+//	@receiver r
+//	@param rank
+//	@return K
+//	@return bool 排名越界时为false
+func (r *SortedSet[K]) Select(rank int) (K, bool) {
+	k, _, ok := r.innerMap.Select(rank)
+	return k, ok
+}
@@ -0,0 +1,369 @@
+package lockx
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DeadlockKind 死锁报告的类型
+type DeadlockKind int
+
+const (
+	// LockOrderInversion 加锁顺序与之前观察到的顺序构成环，可能死锁
+	LockOrderInversion DeadlockKind = iota + 1
+	// LongWait 等待锁的时间超过了阈值
+	LongWait
+)
+
+// String
+//
+//	@Description:
+//	@receiver k
+//	@return string
+func (k DeadlockKind) String() string {
+	switch k {
+	case LockOrderInversion:
+		return "lock order inversion"
+	case LongWait:
+		return "long wait"
+	default:
+		return fmt.Sprintf("DeadlockKind(%d)", int(k))
+	}
+}
+
+// HeldLock 被持有的锁
+type HeldLock struct {
+	Owner int64  // 持有者的goroutine id或Owner
+	Lock  string // 锁的标识，形如 *lockx.ReentrantMutex(0xc000010000)
+	Stack string // 最外层加锁时的调用栈
+}
+
+// LockEdge 加锁顺序图中的一条边，表示曾经在持有From的情况下去获取To
+type LockEdge struct {
+	From  string
+	To    string
+	Stack string // 第一次观察到这个顺序时的调用栈
+}
+
+// DeadlockReport 死锁检测的报告
+type DeadlockReport struct {
+	Kind    DeadlockKind
+	Owner   int64         // 正在加锁的goroutine id或Owner
+	Lock    string        // 正在获取的锁
+	Stack   string        // 正在加锁的调用栈
+	Held    []HeldLock    // 正在加锁的一方已经持有的锁
+	Cycle   []LockEdge    // LockOrderInversion时，与本次加锁构成环的历史加锁顺序
+	Holders []HeldLock    // LongWait时，正在持有这个锁的一方
+	Waited  time.Duration // LongWait时，已经等待的时间
+}
+
+// String
+//
+//	@Description: 多行文本格式的报告
+//	@receiver r
+//	@return string
+func (r DeadlockReport) String() string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "lockx: %s: %d acquiring %s", r.Kind, r.Owner, r.Lock)
+	if r.Kind == LongWait {
+		_, _ = fmt.Fprintf(&sb, " waited %s", r.Waited)
+	}
+	_, _ = fmt.Fprintf(&sb, "\n%s", r.Stack)
+	for _, h := range r.Held {
+		_, _ = fmt.Fprintf(&sb, "\nwhile holding %s, acquired at:\n%s", h.Lock, h.Stack)
+	}
+	for _, e := range r.Cycle {
+		_, _ = fmt.Fprintf(&sb, "\npreviously acquired %s while holding %s at:\n%s", e.To, e.From, e.Stack)
+	}
+	for _, h := range r.Holders {
+		_, _ = fmt.Fprintf(&sb, "\n%s is held by %d, acquired at:\n%s", h.Lock, h.Owner, h.Stack)
+	}
+	return sb.String()
+}
+
+// DeadlockOption 死锁检测的可选配置
+type DeadlockOption func(d *deadlockDetector)
+
+// WithDeadlockHandler
+//
+//	@Description: 设置处理报告的回调，默认输出到标准日志。回调可能在等待锁的goroutine之外调用，回调中不要再获取被检测的锁
+//	@param handler
+//	@return DeadlockOption
+func WithDeadlockHandler(handler func(report DeadlockReport)) DeadlockOption {
+	return func(d *deadlockDetector) {
+		if handler != nil {
+			d.handler = handler
+		}
+	}
+}
+
+// WithWaitThreshold
+//
+//	@Description: 设置等待锁超过多久时报告，默认30秒，0表示不检测等待时间
+//	@param threshold
+//	@return DeadlockOption
+func WithWaitThreshold(threshold time.Duration) DeadlockOption {
+	return func(d *deadlockDetector) {
+		if threshold >= 0 {
+			d.waitThreshold = threshold
+		}
+	}
+}
+
+// activeDetector 当前的死锁检测器，nil表示没有开启
+var activeDetector atomic.Pointer[deadlockDetector]
+
+// EnableDeadlockDetection
+//
+//	@Description: 开启死锁检测，ReentrantMutex和ReentrantRWMutex会记录每个持有者持有的锁以及加锁顺序，
+//	发现加锁顺序构成环或者等待超过阈值时报告。检测会让每次加锁变慢很多，并且记录的顺序图不会释放，只适合调试时使用，
+//	也可以使用 -tags lockx_deadlock 编译，在启动时自动开启。重复调用会清空之前的记录
+//	@param opts
+func EnableDeadlockDetection(opts ...DeadlockOption) {
+	d := &deadlockDetector{
+		handler: func(report DeadlockReport) {
+			log.Default().Println(report.String())
+		},
+		waitThreshold: 30 * time.Second,
+		held:          make(map[int64][]*heldEntry),
+		order:         make(map[any]map[any]string),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	activeDetector.Store(d)
+}
+
+// DisableDeadlockDetection
+//
+//	@Description: 关闭死锁检测并清空记录
+func DisableDeadlockDetection() {
+	activeDetector.Store(nil)
+}
+
+// DeadlockDetectionEnabled
+//
+//	@Description: 是否开启了死锁检测
+//	@return bool
+func DeadlockDetectionEnabled() bool {
+	return activeDetector.Load() != nil
+}
+
+// deadlockDetector 死锁检测器
+type deadlockDetector struct {
+	handler       func(report DeadlockReport)
+	waitThreshold time.Duration
+
+	mu    sync.Mutex
+	held  map[int64][]*heldEntry // 每个持有者持有的锁，按加锁顺序排列
+	order map[any]map[any]string // 加锁顺序图，order[a][b]表示持有a时获取过b，值为第一次观察到时的调用栈
+}
+
+// heldEntry 一个持有者持有的一个锁
+type heldEntry struct {
+	lock  any
+	count int // 重入的次数
+	stack string
+}
+
+// beforeLock
+//
+//	@Description: 获取锁之前调用，检查加锁顺序并开始计时，调用时不能持有任何内部的互斥锁
+//	@receiver d
+//	@param lock
+//	@param id goroutine id或者Owner
+//	@return stop 获取结束（成功或失败）后调用，停止等待计时
+func (d *deadlockDetector) beforeLock(lock any, id int64) (stop func()) {
+	d.mu.Lock()
+	held := d.held[id]
+	for _, h := range held {
+		if h.lock == lock {
+			// 重入不会改变加锁顺序，也不会等待
+			d.mu.Unlock()
+			return func() {}
+		}
+	}
+	stack := callerStack()
+	var reports []DeadlockReport
+	for _, h := range held {
+		if cycle := d.findPath(lock, h.lock); cycle != nil {
+			reports = append(reports, DeadlockReport{
+				Kind:  LockOrderInversion,
+				Owner: id,
+				Lock:  lockName(lock),
+				Stack: stack,
+				Held:  d.heldLocks(id),
+				Cycle: cycle,
+			})
+		}
+		edges := d.order[h.lock]
+		if edges == nil {
+			edges = make(map[any]string)
+			d.order[h.lock] = edges
+		}
+		if _, ok := edges[lock]; !ok {
+			edges[lock] = stack
+		}
+	}
+	d.mu.Unlock()
+	for _, r := range reports {
+		d.handler(r)
+	}
+
+	if d.waitThreshold <= 0 {
+		return func() {}
+	}
+	start := time.Now()
+	timer := time.AfterFunc(d.waitThreshold, func() {
+		d.mu.Lock()
+		r := DeadlockReport{
+			Kind:    LongWait,
+			Owner:   id,
+			Lock:    lockName(lock),
+			Stack:   stack,
+			Held:    d.heldLocks(id),
+			Holders: d.holders(lock),
+			Waited:  time.Since(start),
+		}
+		d.mu.Unlock()
+		d.handler(r)
+	})
+	return func() { timer.Stop() }
+}
+
+// acquired
+//
+//	@Description: 每次加锁成功（包括重入）后调用
+//	@receiver d
+//	@param lock
+//	@param id goroutine id或者Owner
+func (d *deadlockDetector) acquired(lock any, id int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, h := range d.held[id] {
+		if h.lock == lock {
+			h.count++
+			return
+		}
+	}
+	d.held[id] = append(d.held[id], &heldEntry{lock: lock, count: 1, stack: callerStack()})
+}
+
+// released
+//
+//	@Description: 每次解锁（包括重入）后调用
+//	@receiver d
+//	@param lock
+//	@param id goroutine id或者Owner
+func (d *deadlockDetector) released(lock any, id int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	held := d.held[id]
+	for i, h := range held {
+		if h.lock != lock {
+			continue
+		}
+		if h.count--; h.count > 0 {
+			return
+		}
+		if len(held) == 1 {
+			delete(d.held, id)
+		} else {
+			d.held[id] = append(held[:i:i], held[i+1:]...)
+		}
+		return
+	}
+}
+
+// findPath
+//
+//	@Description: 在加锁顺序图中查找from到to的路径，调用时需持有d.mu
+//	@receiver d
+//	@param from
+//	@param to
+//	@return []LockEdge 不存在时为nil
+func (d *deadlockDetector) findPath(from, to any) []LockEdge {
+	visited := map[any]bool{from: true}
+	var dfs func(cur any) []LockEdge
+	dfs = func(cur any) []LockEdge {
+		for next, stack := range d.order[cur] {
+			edge := LockEdge{From: lockName(cur), To: lockName(next), Stack: stack}
+			if next == to {
+				return []LockEdge{edge}
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			if path := dfs(next); path != nil {
+				return append([]LockEdge{edge}, path...)
+			}
+		}
+		return nil
+	}
+	return dfs(from)
+}
+
+// heldLocks
+//
+//	@Description: id持有的锁，调用时需持有d.mu
+//	@receiver d
+//	@param id
+//	@return []HeldLock
+func (d *deadlockDetector) heldLocks(id int64) []HeldLock {
+	var res []HeldLock
+	for _, h := range d.held[id] {
+		res = append(res, HeldLock{Owner: id, Lock: lockName(h.lock), Stack: h.stack})
+	}
+	return res
+}
+
+// holders
+//
+//	@Description: 持有lock的所有持有者，调用时需持有d.mu
+//	@receiver d
+//	@param lock
+//	@return []HeldLock
+func (d *deadlockDetector) holders(lock any) []HeldLock {
+	var res []HeldLock
+	for id, held := range d.held {
+		for _, h := range held {
+			if h.lock == lock {
+				res = append(res, HeldLock{Owner: id, Lock: lockName(lock), Stack: h.stack})
+			}
+		}
+	}
+	return res
+}
+
+// lockName
+//
+//	@Description:
+//	@param lock
+//	@return string
+func lockName(lock any) string {
+	return fmt.Sprintf("%T(%p)", lock, lock)
+}
+
+// callerStack
+//
+//	@Description: lockx包之外的调用栈
+//	@return string
+func callerStack() string {
+	pcs := make([]uintptr, 32)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+	// 跳过lockx内部的调用
+	for len(pcs) > 0 {
+		frame, _ := runtime.CallersFrames(pcs[:1]).Next()
+		if !strings.HasPrefix(frame.Function, "github.com/yuhao-jack/go-toolx/lockx.") {
+			break
+		}
+		pcs = pcs[1:]
+	}
+	return formatStack(pcs)
+}
@@ -0,0 +1,9 @@
+//go:build lockx_deadlock
+// +build lockx_deadlock
+
+package lockx
+
+// 使用 -tags lockx_deadlock 编译时在启动时开启死锁检测
+func init() {
+	EnableDeadlockDetection()
+}
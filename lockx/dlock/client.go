@@ -0,0 +1,494 @@
+package dlock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/yuhao-jack/go-toolx/lockx"
+	"github.com/yuhao-jack/go-toolx/netx"
+	"net"
+	"sync"
+	"time"
+)
+
+// Client 锁服务的客户端
+// @Description: 一个Client对应一个连接，可以被多个goroutine并发使用，连接断开后服务端会释放它持有的所有锁
+type Client struct {
+	pack    *netx.DataPack
+	writeMu sync.Mutex // 一个消息分多次写入，需要串行
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan response // 等待响应的请求
+	done    chan struct{}            // 连接断开后close
+	err     error                    // 连接断开的原因
+}
+
+// Dial
+//
+//	@Description: 连接锁服务
+//	@param addr
+//	@return *Client
+//	@return error
+func Dial(addr string) (*Client, error) {
+	conn, err := netx.CreateTcpConn(addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// NewClient
+//
+//	@Description: 使用已经建立的连接创建客户端
+//	@param conn
+//	@return *Client
+func NewClient(conn net.Conn) *Client {
+	c := &Client{
+		pack:    &netx.DataPack{Conn: conn},
+		pending: make(map[uint64]chan response),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// Close
+//
+//	@Description: 关闭连接，服务端会释放这个客户端持有的所有锁
+//	@receiver c
+//	@return error
+func (c *Client) Close() error {
+	return c.pack.Close()
+}
+
+// Done
+//
+//	@Description: 连接断开后close
+//	@receiver c
+//	@return <-chan struct{}
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err
+//
+//	@Description: 连接断开的原因
+//	@receiver c
+//	@return error 连接正常时为nil
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// readLoop
+//
+//	@Description: 读取响应并交给对应的请求，连接断开后唤醒所有等待者
+//	@receiver c
+func (c *Client) readLoop() {
+	var err error
+	for {
+		var msg netx.IMessage
+		if msg, err = c.pack.UnPackMessage(); err != nil {
+			break
+		}
+		var resp response
+		if err = json.Unmarshal(msg.GetBody(), &resp); err != nil {
+			break
+		}
+		c.mu.Lock()
+		ch, ok := c.pending[resp.ID]
+		delete(c.pending, resp.ID)
+		c.mu.Unlock()
+		if ok {
+			ch <- resp
+		}
+	}
+	_ = c.pack.Close()
+	c.mu.Lock()
+	c.err = err
+	c.pending = nil
+	c.mu.Unlock()
+	close(c.done)
+}
+
+// call
+//
+//	@Description: 发送请求并等待响应。LOCK请求在ctx结束时已经发出的，会在收到响应后释放可能已经获得的锁
+//	@receiver c
+//	@param ctx
+//	@param command
+//	@param req
+//	@return response
+//	@return error 连接断开时返回ErrClientClosed，ctx结束时返回ctx.Err()
+func (c *Client) call(ctx context.Context, command string, req request) (response, error) {
+	if err := ctx.Err(); err != nil {
+		return response{}, err
+	}
+	ch := make(chan response, 1)
+	c.mu.Lock()
+	if c.pending == nil {
+		c.mu.Unlock()
+		return response{}, ErrClientClosed
+	}
+	c.nextID++
+	req.ID = c.nextID
+	c.pending[req.ID] = ch
+	c.mu.Unlock()
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		c.forget(req.ID)
+		return response{}, err
+	}
+	c.writeMu.Lock()
+	err = c.pack.Pack([]byte(command), body)
+	c.writeMu.Unlock()
+	if err != nil {
+		c.forget(req.ID)
+		_ = c.pack.Close()
+		return response{}, ErrClientClosed
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-c.done:
+		return response{}, ErrClientClosed
+	case <-ctx.Done():
+		if command == CmdLock {
+			go c.releaseAbandoned(ch, req.Key)
+		} else {
+			c.forget(req.ID)
+		}
+		return response{}, ctx.Err()
+	}
+}
+
+// forget
+//
+//	@Description: 不再等待id的响应
+//	@receiver c
+//	@param id
+func (c *Client) forget(id uint64) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// releaseAbandoned
+//
+//	@Description: 等待被放弃的LOCK请求的响应，加锁成功时立即解锁
+//	@receiver c
+//	@param ch
+//	@param key
+func (c *Client) releaseAbandoned(ch chan response, key string) {
+	select {
+	case resp := <-ch:
+		if resp.OK {
+			_, _ = c.call(context.Background(), CmdUnlock, request{Key: key, Token: resp.Token})
+		}
+	case <-c.done:
+	}
+}
+
+// LockerOption Locker的可选配置
+type LockerOption func(l *Locker)
+
+// WithTTL
+//
+//	@Description: 设置租约时长，默认DefaultTTL。持有期间每ttl/3自动续租一次。
+//	协议中租约以毫秒为单位，小于等于0时使用默认值，大于0但不足MinTTL时按MinTTL处理
+//	@param ttl
+//	@return LockerOption
+func WithTTL(ttl time.Duration) LockerOption {
+	return func(l *Locker) {
+		if ttl <= 0 {
+			return
+		}
+		if ttl < MinTTL {
+			ttl = MinTTL
+		}
+		l.ttl = ttl
+	}
+}
+
+// WithPollInterval
+//
+//	@Description: 设置锁被其他人持有时重试的间隔，默认50毫秒
+//	@param interval
+//	@return LockerOption
+func WithPollInterval(interval time.Duration) LockerOption {
+	return func(l *Locker) {
+		if interval > 0 {
+			l.poll = interval
+		}
+	}
+}
+
+var _ lockx.ContextLocker = (*Locker)(nil)
+
+// Locker 一个分布式锁
+// @Description: 持有期间自动续租，续租失败或者连接断开时通过Lost通知，此时其他客户端可能已经获得了锁，
+// 访问受保护的资源时应带上Token，由资源一方拒绝过期的token。Locker不可重入，已经持有时再次加锁返回ErrLockerHeld
+type Locker struct {
+	c    *Client
+	key  string
+	ttl  time.Duration
+	poll time.Duration
+
+	mu    sync.Mutex
+	token uint64        // 当前租约的fencing token，0表示没有持有
+	lost  chan struct{} // 当前租约丢失时close
+	stop  chan struct{} // Unlock时close，停止续租
+	wg    sync.WaitGroup
+}
+
+// NewLocker
+//
+//	@Description: 创建名为key的锁，同一个key在所有客户端间互斥
+//	@receiver c
+//	@param key
+//	@param opts
+//	@return *Locker
+func (c *Client) NewLocker(key string, opts ...LockerOption) *Locker {
+	l := &Locker{c: c, key: key, ttl: DefaultTTL, poll: 50 * time.Millisecond}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Key
+//
+//	@Description:
+//	@receiver l
+//	@return string
+func (l *Locker) Key() string {
+	return l.key
+}
+
+// Lock
+//
+//	@Description: 加锁，直到成功，连接断开等错误会panic
+//	@receiver l
+func (l *Locker) Lock() {
+	if err := l.LockContext(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+// TryLock
+//
+//	@Description: 尝试加锁，锁被其他人持有时立即返回
+//	@receiver l
+//	@return bool 是否成功
+func (l *Locker) TryLock() bool {
+	return l.try(context.Background()) == nil
+}
+
+// LockTimeout
+//
+//	@Description: 加锁，最多等待d
+//	@receiver l
+//	@param d
+//	@return bool 是否成功
+func (l *Locker) LockTimeout(d time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return l.LockContext(ctx) == nil
+}
+
+// LockContext
+//
+//	@Description: 加锁，锁被其他人持有时每隔一段时间重试，直到成功或者ctx结束
+//	@receiver l
+//	@param ctx
+//	@return error ctx结束时返回ctx.Err()，已经持有时返回ErrLockerHeld，连接断开时返回ErrClientClosed
+func (l *Locker) LockContext(ctx context.Context) error {
+	for {
+		err := l.try(ctx)
+		if !errors.Is(err, ErrLocked) {
+			return err
+		}
+		timer := time.NewTimer(l.poll)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Unlock
+//
+//	@Description: 解锁，没有持有时会panic。租约已经丢失或者连接已经断开时忽略服务端的错误
+//	@receiver l
+func (l *Locker) Unlock() {
+	token := l.release()
+	if token == 0 {
+		panic("dlock: unlock of unlocked Locker")
+	}
+	_, _ = l.c.call(context.Background(), CmdUnlock, request{Key: l.key, Token: token})
+}
+
+// UnlockContext
+//
+//	@Description: 解锁并返回服务端的结果
+//	@receiver l
+//	@param ctx
+//	@return error 没有持有或者租约已经丢失时返回ErrNotHeld
+func (l *Locker) UnlockContext(ctx context.Context) error {
+	token := l.release()
+	if token == 0 {
+		return ErrNotHeld
+	}
+	resp, err := l.c.call(ctx, CmdUnlock, request{Key: l.key, Token: token})
+	if err != nil {
+		return err
+	}
+	return resp.err()
+}
+
+// Token
+//
+//	@Description: 当前租约的fencing token，每次加锁成功都比之前所有的token大
+//	@receiver l
+//	@return uint64 没有持有时为0
+func (l *Locker) Token() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.token
+}
+
+// Lost
+//
+//	@Description: 当前租约丢失（续租失败或者连接断开）时close，Unlock不会close。丢失后仍需调用Unlock才能再次加锁
+//	@receiver l
+//	@return <-chan struct{} 没有持有时返回nil
+func (l *Locker) Lost() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.token == 0 {
+		return nil
+	}
+	return l.lost
+}
+
+// try
+//
+//	@Description: 发送一次LOCK请求，成功时开始续租
+//	@receiver l
+//	@param ctx
+//	@return error
+func (l *Locker) try(ctx context.Context) error {
+	l.mu.Lock()
+	held := l.token != 0
+	l.mu.Unlock()
+	if held {
+		return ErrLockerHeld
+	}
+	sent := time.Now()
+	resp, err := l.c.call(ctx, CmdLock, request{Key: l.key, TTL: l.ttl.Milliseconds()})
+	if err != nil {
+		return err
+	}
+	if err = resp.err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.token != 0 {
+		// 其他goroutine用这个Locker持有的租约已经在服务端过期，这次又获得了锁，归还多余的租约
+		go func() {
+			_, _ = l.c.call(context.Background(), CmdUnlock, request{Key: l.key, Token: resp.Token})
+		}()
+		return ErrLockerHeld
+	}
+	l.token, l.lost, l.stop = resp.Token, make(chan struct{}), make(chan struct{})
+	l.wg.Add(1)
+	go l.renew(resp.Token, sent.Add(l.ttl), l.lost, l.stop)
+	return nil
+}
+
+// release
+//
+//	@Description: 清除本地的持有状态并停止续租
+//	@receiver l
+//	@return uint64 之前持有的token，没有持有时为0
+func (l *Locker) release() uint64 {
+	l.mu.Lock()
+	token, stop := l.token, l.stop
+	l.token, l.stop = 0, nil
+	l.mu.Unlock()
+	if token != 0 {
+		close(stop)
+		l.wg.Wait()
+	}
+	return token
+}
+
+// renew
+//
+//	@Description: 每ttl/3续租一次，直到stop被close。续租失败、连接断开或者到了本地记录的租约到期时间仍没有续租成功时close lost。
+//	续租请求在单独的goroutine中发送，连接写入阻塞时也能按时发现租约丢失
+//	@receiver l
+//	@param token
+//	@param expires 租约的到期时间，从发出请求时开始计算，比服务端记录的稍早
+//	@param lost
+//	@param stop
+func (l *Locker) renew(token uint64, expires time.Time, lost, stop chan struct{}) {
+	defer l.wg.Done()
+	interval := l.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(time.Until(expires))
+	defer deadline.Stop()
+
+	type result struct {
+		sent time.Time
+		err  error
+	}
+	results := make(chan result, 1)
+	inflight := false
+	for {
+		select {
+		case <-stop:
+			return
+		case <-l.c.done:
+			close(lost)
+			return
+		case <-deadline.C:
+			close(lost)
+			return
+		case <-ticker.C:
+			if inflight {
+				continue
+			}
+			inflight = true
+			go func(sent time.Time) {
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				defer cancel()
+				resp, err := l.c.call(ctx, CmdRenew, request{Key: l.key, TTL: l.ttl.Milliseconds(), Token: token})
+				if err == nil {
+					err = resp.err()
+				}
+				results <- result{sent: sent, err: err}
+			}(time.Now())
+		case r := <-results:
+			inflight = false
+			remaining := time.Until(r.sent.Add(l.ttl))
+			if r.err != nil || remaining <= 0 {
+				close(lost)
+				return
+			}
+			if !deadline.Stop() {
+				<-deadline.C
+			}
+			deadline.Reset(remaining)
+		}
+	}
+}
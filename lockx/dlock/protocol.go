@@ -0,0 +1,66 @@
+// Package dlock
+// @Description: 基于租约的分布式锁服务和客户端，使用netx.DataPack的消息协议通信，消息体为JSON
+package dlock
+
+import (
+	"errors"
+	"time"
+)
+
+// 协议中的命令，请求和响应使用相同的命令
+const (
+	CmdLock   = "LOCK"   // 加锁，成功时返回新的fencing token
+	CmdUnlock = "UNLOCK" // 用token解锁
+	CmdRenew  = "RENEW"  // 用token续租
+)
+
+const (
+	// DefaultTTL 请求中没有指定租约时长时使用的默认值
+	DefaultTTL = 10 * time.Second
+	// MinTTL 协议中租约以毫秒为单位，能表示的最短租约
+	MinTTL = time.Millisecond
+)
+
+var (
+	// ErrNotHeld 解锁或续租时token不是当前的持有者，锁已经过期或者被其他人获取
+	ErrNotHeld = errors.New("dlock: lock not held")
+	// ErrLocked 锁被其他人持有
+	ErrLocked = errors.New("dlock: lock held by another client")
+	// ErrClientClosed 客户端已经关闭或者连接已经断开
+	ErrClientClosed = errors.New("dlock: client closed")
+	// ErrLockerHeld 同一个Locker重复加锁
+	ErrLockerHeld = errors.New("dlock: locker already holds the lock")
+)
+
+// request 请求的消息体
+type request struct {
+	ID    uint64 `json:"id"`               // 请求id，响应中原样返回，用于在一个连接上并发请求
+	Key   string `json:"key"`              // 锁的名字
+	TTL   int64  `json:"ttl_ms,omitempty"` // 租约时长，毫秒，LOCK和RENEW使用
+	Token uint64 `json:"token,omitempty"`  // fencing token，UNLOCK和RENEW使用
+}
+
+// response 响应的消息体
+type response struct {
+	ID    uint64 `json:"id"`
+	OK    bool   `json:"ok"`
+	Token uint64 `json:"token,omitempty"` // LOCK成功时为新的fencing token
+	Error string `json:"error,omitempty"` // 失败的原因，与ErrXxx的文本一致
+}
+
+// err
+//
+//	@Description: 把响应转换成error
+//	@receiver r
+//	@return error OK时为nil
+func (r response) err() error {
+	if r.OK {
+		return nil
+	}
+	for _, err := range []error{ErrNotHeld, ErrLocked} {
+		if r.Error == err.Error() {
+			return err
+		}
+	}
+	return errors.New(r.Error)
+}
@@ -0,0 +1,255 @@
+package dlock
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/yuhao-jack/go-toolx/netx"
+	"net"
+	"sync"
+	"time"
+)
+
+// Server 锁服务
+// @Description: 所有的锁保存在内存中，每个锁有一个租约，到期没有续租的锁可以被其他客户端获取；
+// 每次加锁成功都会分配一个单调递增的fencing token，存储服务可以拒绝token比已见过的更小的写入，防止租约过期的旧持有者写坏数据；
+// 客户端连接断开时，它持有的锁立即释放
+type Server struct {
+	mu       sync.Mutex
+	locks    map[string]*lease
+	fence    uint64 // 最近一次分配的fencing token
+	sessions map[*session]struct{}
+	listener net.Listener
+	closed   bool
+	wg       sync.WaitGroup
+	now      func() time.Time
+}
+
+// lease 一个被持有的锁
+type lease struct {
+	token   uint64
+	owner   *session
+	expires time.Time
+}
+
+// session 一个客户端连接
+type session struct {
+	pack *netx.DataPack
+}
+
+// NewServer
+//
+//	@Description: 创建锁服务
+//	@return *Server
+func NewServer() *Server {
+	return &Server{
+		locks:    make(map[string]*lease),
+		sessions: make(map[*session]struct{}),
+		now:      time.Now,
+	}
+}
+
+// ListenAndServe
+//
+//	@Description: 监听addr并处理连接，直到Close
+//	@receiver s
+//	@param addr
+//	@return error
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := netx.CreateTCPListener(addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve
+//
+//	@Description: 在listener上处理连接，直到Close，Close后返回nil
+//	@receiver s
+//	@param listener
+//	@return error
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = listener.Close()
+		return nil
+	}
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+			return err
+		}
+		sess := &session{pack: &netx.DataPack{Conn: conn}}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return nil
+		}
+		s.sessions[sess] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.serveSession(sess)
+	}
+}
+
+// Close
+//
+//	@Description: 停止监听，断开所有连接并等待处理结束
+//	@receiver s
+//	@return error
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for sess := range s.sessions {
+		_ = sess.pack.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// Holder
+//
+//	@Description: 查询锁当前的持有者
+//	@receiver s
+//	@param key
+//	@return token 当前持有者的fencing token
+//	@return ok 没有被持有或者租约已经过期时为false
+func (s *Server) Holder(key string) (token uint64, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l := s.activeLease(key)
+	if l == nil {
+		return 0, false
+	}
+	return l.token, true
+}
+
+// serveSession
+//
+//	@Description: 处理一个连接上的请求，连接断开时释放它持有的所有锁
+//	@receiver s
+//	@param sess
+func (s *Server) serveSession(sess *session) {
+	defer s.wg.Done()
+	defer s.closeSession(sess)
+	for {
+		msg, err := sess.pack.UnPackMessage()
+		if err != nil {
+			return
+		}
+		var req request
+		resp := response{}
+		if err = json.Unmarshal(msg.GetBody(), &req); err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp = s.handle(sess, string(msg.GetCommand()), req)
+		}
+		body, _ := json.Marshal(resp)
+		if err = sess.pack.Pack(msg.GetCommand(), body); err != nil {
+			return
+		}
+	}
+}
+
+// closeSession
+//
+//	@Description: 关闭连接并释放它持有的所有锁
+//	@receiver s
+//	@param sess
+func (s *Server) closeSession(sess *session) {
+	_ = sess.pack.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sess)
+	for key, l := range s.locks {
+		if l.owner == sess {
+			delete(s.locks, key)
+		}
+	}
+}
+
+// handle
+//
+//	@Description: 处理一个请求
+//	@receiver s
+//	@param sess
+//	@param command
+//	@param req
+//	@return response
+func (s *Server) handle(sess *session, command string, req request) response {
+	resp := response{ID: req.ID}
+	ttl := time.Duration(req.TTL) * time.Millisecond
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l := s.activeLease(req.Key)
+	// token来自全局递增的计数器，可以被猜到，解锁和续租还要求是加锁的那个连接
+	switch command {
+	case CmdLock:
+		if l != nil {
+			resp.Error = ErrLocked.Error()
+			return resp
+		}
+		s.fence++
+		s.locks[req.Key] = &lease{token: s.fence, owner: sess, expires: s.now().Add(ttl)}
+		resp.OK, resp.Token = true, s.fence
+	case CmdUnlock:
+		if l == nil || l.owner != sess || l.token != req.Token {
+			resp.Error = ErrNotHeld.Error()
+			return resp
+		}
+		delete(s.locks, req.Key)
+		resp.OK = true
+	case CmdRenew:
+		if l == nil || l.owner != sess || l.token != req.Token {
+			resp.Error = ErrNotHeld.Error()
+			return resp
+		}
+		l.expires = s.now().Add(ttl)
+		resp.OK, resp.Token = true, l.token
+	default:
+		resp.Error = "dlock: unknown command " + command
+	}
+	return resp
+}
+
+// activeLease
+//
+//	@Description: 未过期的租约，过期的租约会被删除，调用时需持有s.mu
+//	@receiver s
+//	@param key
+//	@return *lease
+func (s *Server) activeLease(key string) *lease {
+	l, ok := s.locks[key]
+	if !ok {
+		return nil
+	}
+	if !s.now().Before(l.expires) {
+		delete(s.locks, key)
+		return nil
+	}
+	return l
+}
@@ -0,0 +1,333 @@
+package lockx
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrFileLockUnsupported 当前平台不支持文件锁
+var ErrFileLockUnsupported = errors.New("lockx: file lock is not supported on this platform")
+
+const (
+	fileLockMinPoll    = time.Millisecond      // 等待文件锁时最短的轮询间隔
+	fileLockMaxPoll    = 50 * time.Millisecond // 等待文件锁时最长的轮询间隔
+	fileLockMaxReaders = 1 << 30               // 进程内同时持有共享锁的上限，独占锁占用全部许可
+)
+
+// FileLock 跨进程的文件锁
+// @Description: 基于flock的建议锁，支持独占（写）和共享（读）两种模式，进程退出时由操作系统自动释放，不会残留。
+// 锁文件在解锁后不会删除，以免与正在打开它的进程产生竞争；加锁后如果发现锁文件已经被删除或替换，会重新打开再加锁。
+// 独占加锁成功后会把当前进程的pid写入锁文件，便于排查是谁持有锁。
+// 同一个FileLock可以被多个goroutine共享：进程内先按读写锁的规则排队（先来先得，等待的独占锁会挡住后来的共享锁），
+// 再去获取文件锁，每个共享锁的持有者各自打开一次锁文件。不可重入，持有独占锁时再次加锁会一直等待。
+// 在同一个进程内不同的FileLock之间同样互斥
+type FileLock struct {
+	path string
+	sem  *Semaphore // 进程内的排队，共享锁占用1个许可，独占锁占用全部许可
+
+	mu     sync.Mutex // 保护files和shared
+	files  []*os.File // 持有锁时打开的锁文件，独占时只有一个，为空表示没有持有
+	shared bool       // 持有的是否是共享锁
+}
+
+// NewFileLock
+//
+//	@Description: 创建文件锁，文件不存在时在加锁时创建
+//	@param path 锁文件的路径
+//	@return *FileLock
+func NewFileLock(path string) *FileLock {
+	return &FileLock{path: path, sem: NewSemaphore(fileLockMaxReaders)}
+}
+
+// Path
+//
+//	@Description: 锁文件的路径
+//	@receiver l
+//	@return string
+func (l *FileLock) Path() string {
+	return l.path
+}
+
+// Lock
+//
+//	@Description: 加独占锁，出错时panic，需要处理错误时使用LockContext
+//	@receiver l
+func (l *FileLock) Lock() {
+	if err := l.LockContext(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+// TryLock
+//
+//	@Description: 尝试加独占锁，不阻塞，出错时返回false
+//	@receiver l
+//	@return bool 是否成功
+func (l *FileLock) TryLock() bool {
+	ok, _ := l.tryLock(false)
+	return ok
+}
+
+// LockTimeout
+//
+//	@Description: 加独占锁，最多等待d，出错时返回false
+//	@receiver l
+//	@param d
+//	@return bool 是否成功
+func (l *FileLock) LockTimeout(d time.Duration) bool {
+	return withTimeout(d, l.LockContext)
+}
+
+// LockContext
+//
+//	@Description: 加独占锁，直到成功、出错或者ctx结束
+//	@receiver l
+//	@param ctx
+//	@return error
+func (l *FileLock) LockContext(ctx context.Context) error {
+	return l.lockContext(ctx, false)
+}
+
+// Unlock
+//
+//	@Description: 释放独占锁，没有持有独占锁时panic
+//	@receiver l
+func (l *FileLock) Unlock() {
+	l.unlock(false)
+}
+
+// RLock
+//
+//	@Description: 加共享锁，出错时panic，需要处理错误时使用RLockContext
+//	@receiver l
+func (l *FileLock) RLock() {
+	if err := l.RLockContext(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+// TryRLock
+//
+//	@Description: 尝试加共享锁，不阻塞，出错时返回false
+//	@receiver l
+//	@return bool 是否成功
+func (l *FileLock) TryRLock() bool {
+	ok, _ := l.tryLock(true)
+	return ok
+}
+
+// RLockTimeout
+//
+//	@Description: 加共享锁，最多等待d，出错时返回false
+//	@receiver l
+//	@param d
+//	@return bool 是否成功
+func (l *FileLock) RLockTimeout(d time.Duration) bool {
+	return withTimeout(d, l.RLockContext)
+}
+
+// RLockContext
+//
+//	@Description: 加共享锁，直到成功、出错或者ctx结束
+//	@receiver l
+//	@param ctx
+//	@return error
+func (l *FileLock) RLockContext(ctx context.Context) error {
+	return l.lockContext(ctx, true)
+}
+
+// RUnlock
+//
+//	@Description: 释放共享锁，没有持有共享锁时panic
+//	@receiver l
+func (l *FileLock) RUnlock() {
+	l.unlock(true)
+}
+
+// Locked
+//
+//	@Description: 当前FileLock是否持有锁（任意goroutine持有独占锁或者共享锁）
+//	@receiver l
+//	@return bool
+func (l *FileLock) Locked() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.files) > 0
+}
+
+// HolderPID
+//
+//	@Description: 读取锁文件中记录的最近一次获得独占锁的进程pid，锁已经释放时pid可能已经不存在
+//	@receiver l
+//	@return int
+//	@return error 文件不存在或者没有记录pid时返回错误
+func (l *FileLock) HolderPID() (int, error) {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// lockContext
+//
+//	@Description: 先在进程内排队，再轮询加文件锁，轮询间隔从fileLockMinPoll开始翻倍直到fileLockMaxPoll
+//	@receiver l
+//	@param ctx
+//	@param shared
+//	@return error
+func (l *FileLock) lockContext(ctx context.Context, shared bool) error {
+	weight := fileLockWeight(shared)
+	if err := l.sem.Acquire(ctx, weight); err != nil {
+		return err
+	}
+	poll := fileLockMinPoll
+	for {
+		ok, err := l.openAndLock(shared)
+		if ok {
+			return nil
+		}
+		if err != nil {
+			l.sem.Release(weight)
+			return err
+		}
+		timer := time.NewTimer(poll)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.sem.Release(weight)
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if poll *= 2; poll > fileLockMaxPoll {
+			poll = fileLockMaxPoll
+		}
+	}
+}
+
+// tryLock
+//
+//	@Description: 尝试加锁一次
+//	@receiver l
+//	@param shared
+//	@return bool
+//	@return error
+func (l *FileLock) tryLock(shared bool) (bool, error) {
+	weight := fileLockWeight(shared)
+	if !l.sem.TryAcquire(weight) {
+		return false, nil
+	}
+	ok, err := l.openAndLock(shared)
+	if !ok {
+		l.sem.Release(weight)
+	}
+	return ok, err
+}
+
+// openAndLock
+//
+//	@Description: 已经在进程内获得许可后，打开锁文件并尝试加文件锁一次
+//	@receiver l
+//	@param shared
+//	@return bool
+//	@return error
+func (l *FileLock) openAndLock(shared bool) (bool, error) {
+	for {
+		f, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0o644)
+		if err != nil {
+			return false, err
+		}
+		ok, err := lockFile(f, shared)
+		if !ok || err != nil {
+			_ = f.Close()
+			return false, err
+		}
+		// 打开文件到加锁成功之间，锁文件可能被其他进程删除或替换，此时锁住的是一个已经没人能打开的文件，需要重新加锁
+		if !sameFile(f, l.path) {
+			_ = unlockFile(f)
+			_ = f.Close()
+			continue
+		}
+		if !shared {
+			if err = writePID(f); err != nil {
+				_ = unlockFile(f)
+				_ = f.Close()
+				return false, err
+			}
+		}
+		l.mu.Lock()
+		l.files, l.shared = append(l.files, f), shared
+		l.mu.Unlock()
+		return true, nil
+	}
+}
+
+// unlock
+//
+//	@Description: 共享锁的持有者之间没有区别，任意释放一个
+//	@receiver l
+//	@param shared
+func (l *FileLock) unlock(shared bool) {
+	l.mu.Lock()
+	if len(l.files) == 0 || l.shared != shared {
+		l.mu.Unlock()
+		panic("lockx: unlock of unlocked FileLock " + l.path)
+	}
+	f := l.files[len(l.files)-1]
+	l.files[len(l.files)-1] = nil
+	l.files = l.files[:len(l.files)-1]
+	l.mu.Unlock()
+	_ = unlockFile(f)
+	_ = f.Close()
+	l.sem.Release(fileLockWeight(shared))
+}
+
+// fileLockWeight
+//
+//	@Description: 进程内排队时占用的许可数
+//	@param shared
+//	@return int64
+func fileLockWeight(shared bool) int64 {
+	if shared {
+		return 1
+	}
+	return fileLockMaxReaders
+}
+
+// sameFile
+//
+//	@Description: 打开的文件与path当前指向的文件是否是同一个
+//	@param f
+//	@param path
+//	@return bool
+func sameFile(f *os.File, path string) bool {
+	opened, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	current, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(opened, current)
+}
+
+// writePID
+//
+//	@Description: 把当前进程的pid写入锁文件
+//	@param f
+//	@return error
+func writePID(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	return err
+}
+
+var _ ContextRWLocker = (*FileLock)(nil)
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package lockx
+
+import "os"
+
+// lockFile 当前平台不支持文件锁
+func lockFile(_ *os.File, _ bool) (bool, error) {
+	return false, ErrFileLockUnsupported
+}
+
+// unlockFile 当前平台不支持文件锁
+func unlockFile(_ *os.File) error {
+	return ErrFileLockUnsupported
+}
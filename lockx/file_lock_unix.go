@@ -0,0 +1,46 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package lockx
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile
+//
+//	@Description: 非阻塞地对文件加flock
+//	@param f
+//	@param shared 是否加共享锁
+//	@return bool 是否成功，被其他人持有时为false
+//	@return error
+func lockFile(f *os.File, shared bool) (bool, error) {
+	how := syscall.LOCK_EX | syscall.LOCK_NB
+	if shared {
+		how = syscall.LOCK_SH | syscall.LOCK_NB
+	}
+	for {
+		err := syscall.Flock(int(f.Fd()), how)
+		switch {
+		case err == nil:
+			return true, nil
+		case errors.Is(err, syscall.EINTR):
+			continue
+		case errors.Is(err, syscall.EWOULDBLOCK):
+			return false, nil
+		default:
+			return false, &os.PathError{Op: "flock", Path: f.Name(), Err: err}
+		}
+	}
+}
+
+// unlockFile
+//
+//	@Description: 释放文件上的flock
+//	@param f
+//	@return error
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,486 @@
+package lockx
+
+import (
+	"context"
+	"fmt"
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LockStats 锁的统计信息快照，时间字段序列化为纳秒
+type LockStats struct {
+	Name         string        `json:"name"`
+	Acquisitions uint64        `json:"acquisitions"`   // 加锁成功的次数，重入也计算在内
+	Contended    uint64        `json:"contended"`      // 需要等待才加锁成功的次数
+	Failed       uint64        `json:"failed"`         // TryLock失败以及超时、取消的次数
+	TotalWait    time.Duration `json:"total_wait"`     // 累计等待时间
+	MaxWait      time.Duration `json:"max_wait"`       // 最长的一次等待时间
+	TotalHold    time.Duration `json:"total_hold"`     // 累计持有时间，重入时从最外层加锁算到最外层解锁
+	MaxHold      time.Duration `json:"max_hold"`       // 最长的一次持有时间
+	MaxHoldStack string        `json:"max_hold_stack"` // 持有时间最长的那一次加锁时的调用栈
+
+	ReadAcquisitions uint64        `json:"read_acquisitions,omitempty"` // 读锁加锁成功的次数
+	ReadContended    uint64        `json:"read_contended,omitempty"`    // 读锁需要等待的次数
+	ReadFailed       uint64        `json:"read_failed,omitempty"`       // 读锁TryRLock失败以及超时、取消的次数
+	ReadTotalWait    time.Duration `json:"read_total_wait,omitempty"`   // 读锁累计等待时间
+	ReadMaxWait      time.Duration `json:"read_max_wait,omitempty"`     // 读锁最长的一次等待时间
+}
+
+// InstrumentOption 创建带统计的锁时的可选配置
+type InstrumentOption func(o *instrumentOptions)
+
+type instrumentOptions struct {
+	stackDepth int  // 记录调用栈的最大层数，0表示不记录
+	register   bool // 是否注册到全局，可以通过Snapshot获取
+}
+
+// WithStackDepth
+//
+//	@Description: 设置记录持有者调用栈的最大层数，默认16，0表示不记录，可以减少每次加锁的开销
+//	@param depth
+//	@return InstrumentOption
+func WithStackDepth(depth int) InstrumentOption {
+	return func(o *instrumentOptions) {
+		if depth >= 0 {
+			o.stackDepth = depth
+		}
+	}
+}
+
+// WithoutRegister
+//
+//	@Description: 不注册到全局，只能通过Stats获取统计信息
+//	@return InstrumentOption
+func WithoutRegister() InstrumentOption {
+	return func(o *instrumentOptions) {
+		o.register = false
+	}
+}
+
+// statsProvider 可以输出统计信息的锁
+type statsProvider interface {
+	Stats() LockStats
+	ResetStats()
+}
+
+// instrumentedLocks 全局注册的带统计的锁，key是名字
+var instrumentedLocks = containerx.NewConcurrentMap[string, statsProvider]()
+
+// Snapshot
+//
+//	@Description: 所有已注册的锁的统计信息，按名字排序
+//	@return []LockStats
+func Snapshot() []LockStats {
+	res := make([]LockStats, 0, instrumentedLocks.Len())
+	instrumentedLocks.Range(func(_ string, p statsProvider) bool {
+		res = append(res, p.Stats())
+		return true
+	})
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// ResetStats
+//
+//	@Description: 清空所有已注册的锁的统计信息
+func ResetStats() {
+	instrumentedLocks.Range(func(_ string, p statsProvider) bool {
+		p.ResetStats()
+		return true
+	})
+}
+
+// Unregister
+//
+//	@Description: 取消注册，之后Snapshot不再包含这个锁
+//	@param name
+func Unregister(name string) {
+	instrumentedLocks.Remove(name)
+}
+
+// lockRecorder 统计信息的记录器
+type lockRecorder struct {
+	mu          sync.Mutex
+	stats       LockStats
+	maxHoldPCs  []uintptr // 持有时间最长的那一次加锁时的调用栈
+	stackDepth  int
+	depth       int       // 当前持有者重入的层数
+	acquiredAt  time.Time // 最外层加锁的时间
+	acquiredPCs []uintptr // 最外层加锁时的调用栈
+}
+
+// newLockRecorder
+//
+//	@Description:
+//	@param name
+//	@param opts
+//	@return *lockRecorder
+//	@return instrumentOptions
+func newLockRecorder(name string, opts []InstrumentOption) (*lockRecorder, instrumentOptions) {
+	o := instrumentOptions{stackDepth: 16, register: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &lockRecorder{stats: LockStats{Name: name}, stackDepth: o.stackDepth}, o
+}
+
+// acquired
+//
+//	@Description: 加锁成功后调用，调用时持有被统计的锁
+//	@receiver r
+//	@param start 开始加锁的时间
+//	@param contended 是否需要等待
+func (r *lockRecorder) acquired(start time.Time, contended bool) {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.depth++
+	if r.depth == 1 {
+		r.acquiredAt = now
+		if r.stackDepth > 0 {
+			pcs := make([]uintptr, r.stackDepth)
+			// 跳过runtime.Callers、acquired和Instrumented的加锁方法
+			r.acquiredPCs = pcs[:runtime.Callers(3, pcs)]
+		}
+	}
+	wait := now.Sub(start)
+	r.stats.Acquisitions++
+	r.stats.TotalWait += wait
+	if contended {
+		r.stats.Contended++
+	}
+	if wait > r.stats.MaxWait {
+		r.stats.MaxWait = wait
+	}
+}
+
+// unlock
+//
+//	@Description: 解锁被统计的锁，成功后记录持有时间。非持有者解锁时被包装的锁会panic，此时不修改任何记录；
+//	解锁时持有r.mu，新的持有者要等记录完成后才能记录自己的加锁，两者不会交错
+//	@receiver r
+//	@param locker 被统计的锁
+func (r *lockRecorder) unlock(locker sync.Locker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	locker.Unlock()
+	r.depth--
+	if r.depth != 0 {
+		return
+	}
+	hold := time.Since(r.acquiredAt)
+	r.stats.TotalHold += hold
+	if hold > r.stats.MaxHold {
+		r.stats.MaxHold = hold
+		r.maxHoldPCs = r.acquiredPCs
+	}
+}
+
+// readAcquired
+//
+//	@Description: 加读锁成功后调用
+//	@receiver r
+//	@param start 开始加锁的时间
+//	@param contended 是否需要等待
+func (r *lockRecorder) readAcquired(start time.Time, contended bool) {
+	wait := time.Since(start)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.ReadAcquisitions++
+	r.stats.ReadTotalWait += wait
+	if contended {
+		r.stats.ReadContended++
+	}
+	if wait > r.stats.ReadMaxWait {
+		r.stats.ReadMaxWait = wait
+	}
+}
+
+// failed
+//
+//	@Description: 加锁失败后调用
+//	@receiver r
+//	@param read 是否是读锁
+func (r *lockRecorder) failed(read bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if read {
+		r.stats.ReadFailed++
+	} else {
+		r.stats.Failed++
+	}
+}
+
+// Stats
+//
+//	@Description: 统计信息快照
+//	@receiver r
+//	@return LockStats
+func (r *lockRecorder) Stats() LockStats {
+	r.mu.Lock()
+	stats, pcs := r.stats, r.maxHoldPCs
+	r.mu.Unlock()
+	stats.MaxHoldStack = formatStack(pcs)
+	return stats
+}
+
+// ResetStats
+//
+//	@Description: 清空统计信息，不影响当前的持有状态
+//	@receiver r
+func (r *lockRecorder) ResetStats() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats = LockStats{Name: r.stats.Name}
+	r.maxHoldPCs = nil
+}
+
+// formatStack
+//
+//	@Description: 把调用栈格式化成与panic输出类似的文本
+//	@param pcs
+//	@return string
+func formatStack(pcs []uintptr) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		_, _ = fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// InstrumentedLocker 记录等待时间、持有时间、加锁次数和持有最久的调用栈的锁包装，解锁必须由持有者调用
+type InstrumentedLocker struct {
+	locker   ContextLocker
+	recorder *lockRecorder
+}
+
+// Instrument
+//
+//	@Description: 包装一个锁，默认以name注册到全局，同名的锁会被替换
+//	@param name
+//	@param locker 被包装的锁，例如*ReentrantMutex
+//	@param opts
+//	@return *InstrumentedLocker
+func Instrument(name string, locker ContextLocker, opts ...InstrumentOption) *InstrumentedLocker {
+	recorder, o := newLockRecorder(name, opts)
+	l := &InstrumentedLocker{locker: locker, recorder: recorder}
+	if o.register {
+		instrumentedLocks.Set(name, l)
+	}
+	return l
+}
+
+// Lock
+//
+//	@Description:
+//	@receiver l
+func (l *InstrumentedLocker) Lock() {
+	start := time.Now()
+	if l.locker.TryLock() {
+		l.recorder.acquired(start, false)
+		return
+	}
+	l.locker.Lock()
+	l.recorder.acquired(start, true)
+}
+
+// TryLock
+//
+//	@Description:
+//	@receiver l
+//	@return bool
+func (l *InstrumentedLocker) TryLock() bool {
+	start := time.Now()
+	if !l.locker.TryLock() {
+		l.recorder.failed(false)
+		return false
+	}
+	l.recorder.acquired(start, false)
+	return true
+}
+
+// LockTimeout
+//
+//	@Description:
+//	@receiver l
+//	@param d
+//	@return bool
+func (l *InstrumentedLocker) LockTimeout(d time.Duration) bool {
+	start := time.Now()
+	if l.locker.TryLock() {
+		l.recorder.acquired(start, false)
+		return true
+	}
+	if !l.locker.LockTimeout(d) {
+		l.recorder.failed(false)
+		return false
+	}
+	l.recorder.acquired(start, true)
+	return true
+}
+
+// LockContext
+//
+//	@Description:
+//	@receiver l
+//	@param ctx
+//	@return error
+func (l *InstrumentedLocker) LockContext(ctx context.Context) error {
+	start := time.Now()
+	if l.locker.TryLock() {
+		l.recorder.acquired(start, false)
+		return nil
+	}
+	if err := l.locker.LockContext(ctx); err != nil {
+		l.recorder.failed(false)
+		return err
+	}
+	l.recorder.acquired(start, true)
+	return nil
+}
+
+// Unlock
+//
+//	@Description:
+//	@receiver l
+func (l *InstrumentedLocker) Unlock() {
+	l.recorder.unlock(l.locker)
+}
+
+// Stats
+//
+//	@Description: 统计信息快照
+//	@receiver l
+//	@return LockStats
+func (l *InstrumentedLocker) Stats() LockStats {
+	return l.recorder.Stats()
+}
+
+// ResetStats
+//
+//	@Description: 清空统计信息
+//	@receiver l
+func (l *InstrumentedLocker) ResetStats() {
+	l.recorder.ResetStats()
+}
+
+// InstrumentedRWLocker 带统计的读写锁包装，写锁的统计与InstrumentedLocker相同，读锁只统计次数和等待时间
+type InstrumentedRWLocker struct {
+	InstrumentedLocker
+	rwLocker ContextRWLocker
+}
+
+// InstrumentRW
+//
+//	@Description: 包装一个读写锁，默认以name注册到全局，同名的锁会被替换
+//	@param name
+//	@param locker 被包装的读写锁，例如*ReentrantRWMutex
+//	@param opts
+//	@return *InstrumentedRWLocker
+func InstrumentRW(name string, locker ContextRWLocker, opts ...InstrumentOption) *InstrumentedRWLocker {
+	recorder, o := newLockRecorder(name, opts)
+	l := &InstrumentedRWLocker{
+		InstrumentedLocker: InstrumentedLocker{locker: locker, recorder: recorder},
+		rwLocker:           locker,
+	}
+	if o.register {
+		instrumentedLocks.Set(name, l)
+	}
+	return l
+}
+
+// RLock
+//
+//	@Description:
+//	@receiver l
+func (l *InstrumentedRWLocker) RLock() {
+	start := time.Now()
+	if l.rwLocker.TryRLock() {
+		l.recorder.readAcquired(start, false)
+		return
+	}
+	l.rwLocker.RLock()
+	l.recorder.readAcquired(start, true)
+}
+
+// TryRLock
+//
+//	@Description:
+//	@receiver l
+//	@return bool
+func (l *InstrumentedRWLocker) TryRLock() bool {
+	start := time.Now()
+	if !l.rwLocker.TryRLock() {
+		l.recorder.failed(true)
+		return false
+	}
+	l.recorder.readAcquired(start, false)
+	return true
+}
+
+// RLockTimeout
+//
+//	@Description:
+//	@receiver l
+//	@param d
+//	@return bool
+func (l *InstrumentedRWLocker) RLockTimeout(d time.Duration) bool {
+	start := time.Now()
+	if l.rwLocker.TryRLock() {
+		l.recorder.readAcquired(start, false)
+		return true
+	}
+	if !l.rwLocker.RLockTimeout(d) {
+		l.recorder.failed(true)
+		return false
+	}
+	l.recorder.readAcquired(start, true)
+	return true
+}
+
+// RLockContext
+//
+//	@Description:
+//	@receiver l
+//	@param ctx
+//	@return error
+func (l *InstrumentedRWLocker) RLockContext(ctx context.Context) error {
+	start := time.Now()
+	if l.rwLocker.TryRLock() {
+		l.recorder.readAcquired(start, false)
+		return nil
+	}
+	if err := l.rwLocker.RLockContext(ctx); err != nil {
+		l.recorder.failed(true)
+		return err
+	}
+	l.recorder.readAcquired(start, true)
+	return nil
+}
+
+// RUnlock
+//
+//	@Description:
+//	@receiver l
+func (l *InstrumentedRWLocker) RUnlock() {
+	l.rwLocker.RUnlock()
+}
+
+var (
+	_ ContextLocker   = (*InstrumentedLocker)(nil)
+	_ ContextRWLocker = (*InstrumentedRWLocker)(nil)
+)
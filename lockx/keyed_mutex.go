@@ -0,0 +1,346 @@
+package lockx
+
+import (
+	"context"
+	"fmt"
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// keyedShardCount 引用计数模式下的分片数，减少不同key之间对分片锁的竞争
+const keyedShardCount = 32
+
+// KeyedMutex [K comparable]
+// @Description: 按key加锁的读写锁，不同key之间互不影响，锁不可重入。
+// 默认使用引用计数模式：每个key的锁在第一次使用时创建，没有持有者和等待者后立即释放，内存占用与活跃的key数量成正比；
+// 分片模式下key按hash映射到固定数量的锁上，内存占用固定，但hash到同一分片的不同key会互斥。零值不可用
+type KeyedMutex[K comparable] struct {
+	shards  []*keyedShard[K]
+	mask    uintptr
+	hasher  containerx.Hasher[K]
+	striped bool
+}
+
+// keyedShard [K comparable]
+// @Description: 一个分片，mu保护entries以及其中每个keyedEntry的状态
+type keyedShard[K comparable] struct {
+	mu      sync.Mutex
+	entries map[K]*keyedEntry // 引用计数模式下使用
+	stripe  *keyedEntry       // 分片模式下使用
+}
+
+// keyedEntry 一把读写锁的状态
+type keyedEntry struct {
+	writer         bool     // 是否有写者持有
+	readers        int      // 持有读锁的数量
+	waitingWriters int      // 正在等待写锁的数量
+	refs           int      // 持有者和等待者的数量，为0时在引用计数模式下被删除
+	released       notifier // 锁被释放或者等待的写者放弃时通知等待者
+}
+
+// NewKeyedMutex [K comparable]
+//
+//	@Description: 创建引用计数模式的KeyedMutex
+//	@return *KeyedMutex[K]
+func NewKeyedMutex[K comparable]() *KeyedMutex[K] {
+	m := &KeyedMutex[K]{
+		shards: make([]*keyedShard[K], keyedShardCount),
+		mask:   keyedShardCount - 1,
+		hasher: containerx.NewHasher[K](),
+	}
+	for i := range m.shards {
+		m.shards[i] = &keyedShard[K]{entries: make(map[K]*keyedEntry)}
+	}
+	return m
+}
+
+// NewStripedKeyedMutex [K comparable]
+//
+//	@Description: 创建分片模式的KeyedMutex
+//	@param stripes 锁的数量，会向上取整为2的幂，小于1时按1处理
+//	@return *KeyedMutex[K]
+func NewStripedKeyedMutex[K comparable](stripes int) *KeyedMutex[K] {
+	n := 1
+	if stripes > 1 {
+		n = 1 << bits.Len(uint(stripes-1))
+	}
+	m := &KeyedMutex[K]{
+		shards:  make([]*keyedShard[K], n),
+		mask:    uintptr(n - 1),
+		hasher:  containerx.NewHasher[K](),
+		striped: true,
+	}
+	for i := range m.shards {
+		m.shards[i] = &keyedShard[K]{stripe: &keyedEntry{}}
+	}
+	return m
+}
+
+// Lock
+//
+//	@Description: 对key加写锁
+//	@receiver m
+//	@param key
+func (m *KeyedMutex[K]) Lock(key K) {
+	_ = m.lockContext(context.Background(), key, true)
+}
+
+// TryLock
+//
+//	@Description: 尝试对key加写锁，不阻塞
+//	@receiver m
+//	@param key
+//	@return bool 是否成功
+func (m *KeyedMutex[K]) TryLock(key K) bool {
+	return m.tryLock(key, true)
+}
+
+// LockTimeout
+//
+//	@Description: 对key加写锁，最多等待d
+//	@receiver m
+//	@param key
+//	@param d
+//	@return bool 是否成功
+func (m *KeyedMutex[K]) LockTimeout(key K, d time.Duration) bool {
+	return withTimeout(d, func(ctx context.Context) error {
+		return m.lockContext(ctx, key, true)
+	})
+}
+
+// LockContext
+//
+//	@Description: 对key加写锁，直到成功或者ctx结束
+//	@receiver m
+//	@param ctx
+//	@param key
+//	@return error ctx结束时返回ctx.Err()
+func (m *KeyedMutex[K]) LockContext(ctx context.Context, key K) error {
+	return m.lockContext(ctx, key, true)
+}
+
+// Unlock
+//
+//	@Description: 释放key的写锁，key没有被加写锁时会panic
+//	@receiver m
+//	@param key
+func (m *KeyedMutex[K]) Unlock(key K) {
+	m.unlock(key, true)
+}
+
+// RLock
+//
+//	@Description: 对key加读锁
+//	@receiver m
+//	@param key
+func (m *KeyedMutex[K]) RLock(key K) {
+	_ = m.lockContext(context.Background(), key, false)
+}
+
+// TryRLock
+//
+//	@Description: 尝试对key加读锁，不阻塞
+//	@receiver m
+//	@param key
+//	@return bool 是否成功
+func (m *KeyedMutex[K]) TryRLock(key K) bool {
+	return m.tryLock(key, false)
+}
+
+// RLockTimeout
+//
+//	@Description: 对key加读锁，最多等待d
+//	@receiver m
+//	@param key
+//	@param d
+//	@return bool 是否成功
+func (m *KeyedMutex[K]) RLockTimeout(key K, d time.Duration) bool {
+	return withTimeout(d, func(ctx context.Context) error {
+		return m.lockContext(ctx, key, false)
+	})
+}
+
+// RLockContext
+//
+//	@Description: 对key加读锁，直到成功或者ctx结束
+//	@receiver m
+//	@param ctx
+//	@param key
+//	@return error ctx结束时返回ctx.Err()
+func (m *KeyedMutex[K]) RLockContext(ctx context.Context, key K) error {
+	return m.lockContext(ctx, key, false)
+}
+
+// RUnlock
+//
+//	@Description: 释放key的读锁，key没有被加读锁时会panic
+//	@receiver m
+//	@param key
+func (m *KeyedMutex[K]) RUnlock(key K) {
+	m.unlock(key, false)
+}
+
+// Len
+//
+//	@Description: 当前被持有或者等待中的锁的数量，分片模式下为分片数
+//	@receiver m
+//	@return int
+func (m *KeyedMutex[K]) Len() int {
+	if m.striped {
+		return len(m.shards)
+	}
+	n := 0
+	for _, s := range m.shards {
+		s.mu.Lock()
+		n += len(s.entries)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// shard
+//
+//	@Description:
+//	@receiver m
+//	@param key
+//	@return *keyedShard[K]
+func (m *KeyedMutex[K]) shard(key K) *keyedShard[K] {
+	return m.shards[m.hasher.Hash(key)&m.mask]
+}
+
+// tryLock
+//
+//	@Description:
+//	@receiver m
+//	@param key
+//	@param write 是否加写锁
+//	@return bool
+func (m *KeyedMutex[K]) tryLock(key K, write bool) bool {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.acquire(key)
+	if e.tryLock(write) {
+		return true
+	}
+	s.release(key, e)
+	return false
+}
+
+// lockContext
+//
+//	@Description:
+//	@receiver m
+//	@param ctx
+//	@param key
+//	@param write 是否加写锁
+//	@return error
+func (m *KeyedMutex[K]) lockContext(ctx context.Context, key K, write bool) error {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.acquire(key)
+	for !e.tryLock(write) {
+		if write {
+			e.waitingWriters++
+		}
+		err := e.released.wait(ctx, &s.mu)
+		if write {
+			e.waitingWriters--
+			if err != nil && e.waitingWriters == 0 {
+				// 放弃等待后，被这个写者挡住的读者可能可以继续了
+				e.released.broadcast()
+			}
+		}
+		if err != nil {
+			s.release(key, e)
+			return err
+		}
+	}
+	return nil
+}
+
+// unlock
+//
+//	@Description:
+//	@receiver m
+//	@param key
+//	@param write 是否释放写锁
+func (m *KeyedMutex[K]) unlock(key K, write bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.stripe
+	if !m.striped {
+		e = s.entries[key]
+	}
+	switch {
+	case write && (e == nil || !e.writer):
+		panic(fmt.Sprintf("lockx: unlock of unlocked key %v", key))
+	case !write && (e == nil || e.readers == 0):
+		panic(fmt.Sprintf("lockx: runlock of unlocked key %v", key))
+	case write:
+		e.writer = false
+		e.released.broadcast()
+	default:
+		e.readers--
+		if e.readers == 0 {
+			e.released.broadcast()
+		}
+	}
+	s.release(key, e)
+}
+
+// acquire
+//
+//	@Description: 获取key对应的锁并增加引用计数，调用时需持有s.mu
+//	@receiver s
+//	@param key
+//	@return *keyedEntry
+func (s *keyedShard[K]) acquire(key K) *keyedEntry {
+	e := s.stripe
+	if e == nil {
+		if e = s.entries[key]; e == nil {
+			e = &keyedEntry{}
+			s.entries[key] = e
+		}
+	}
+	e.refs++
+	return e
+}
+
+// release
+//
+//	@Description: 减少引用计数，引用计数模式下为0时删除，调用时需持有s.mu
+//	@receiver s
+//	@param key
+//	@param e
+func (s *keyedShard[K]) release(key K, e *keyedEntry) {
+	e.refs--
+	if e.refs == 0 && s.stripe == nil {
+		delete(s.entries, key)
+	}
+}
+
+// tryLock
+//
+//	@Description: 有写者持有或等待时不能加读锁，有任何持有者时不能加写锁
+//	@receiver e
+//	@param write
+//	@return bool
+func (e *keyedEntry) tryLock(write bool) bool {
+	if write {
+		if e.writer || e.readers > 0 {
+			return false
+		}
+		e.writer = true
+		return true
+	}
+	if e.writer || e.waitingWriters > 0 {
+		return false
+	}
+	e.readers++
+	return true
+}
@@ -0,0 +1,87 @@
+package lockx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ContextLocker 支持非阻塞、超时和取消的锁
+type ContextLocker interface {
+	sync.Locker
+	// TryLock 尝试加锁，不阻塞，返回是否成功
+	TryLock() bool
+	// LockTimeout 最多等待d，返回是否成功
+	LockTimeout(d time.Duration) bool
+	// LockContext 等待直到加锁成功或者ctx结束，失败时返回ctx.Err()
+	LockContext(ctx context.Context) error
+}
+
+// ContextRWLocker 支持非阻塞、超时和取消的读写锁
+type ContextRWLocker interface {
+	ContextLocker
+	RLock()
+	RUnlock()
+	// TryRLock 尝试加读锁，不阻塞，返回是否成功
+	TryRLock() bool
+	// RLockTimeout 加读锁，最多等待d，返回是否成功
+	RLockTimeout(d time.Duration) bool
+	// RLockContext 加读锁，等待直到成功或者ctx结束，失败时返回ctx.Err()
+	RLockContext(ctx context.Context) error
+}
+
+var (
+	_ ContextLocker   = (*ReentrantMutex)(nil)
+	_ ContextRWLocker = (*ReentrantRWMutex)(nil)
+)
+
+// notifier
+// @Description: 基于channel的条件变量，与sync.Cond不同的是等待可以被context取消，字段由外部的互斥锁保护
+type notifier struct {
+	ch chan struct{} // 调用broadcast时close，唤醒所有等待者
+}
+
+// wait
+//
+//	@Description: 释放mu等待broadcast或者ctx结束，返回前重新持有mu
+//	@receiver n
+//	@param ctx
+//	@param mu 调用时必须已持有
+//	@return error ctx结束时返回ctx.Err()
+func (n *notifier) wait(ctx context.Context, mu *sync.Mutex) error {
+	if n.ch == nil {
+		n.ch = make(chan struct{})
+	}
+	ch := n.ch
+	mu.Unlock()
+	defer mu.Lock()
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// broadcast
+//
+//	@Description: 唤醒所有等待者，调用时必须持有保护n的互斥锁
+//	@receiver n
+func (n *notifier) broadcast() {
+	if n.ch != nil {
+		close(n.ch)
+		n.ch = nil
+	}
+}
+
+// withTimeout
+//
+//	@Description: 用LockContext形式的函数实现LockTimeout，锁空闲时即使d<=0也能成功
+//	@param d
+//	@param lockContext
+//	@return bool
+func withTimeout(d time.Duration, lockContext func(ctx context.Context) error) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return lockContext(ctx) == nil
+}
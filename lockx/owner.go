@@ -0,0 +1,50 @@
+package lockx
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// stackBufPool 复用GetGoroutineID读取栈信息的缓冲区，避免每次加锁都分配内存
+var stackBufPool = sync.Pool{
+	New: func() any {
+		return new([64]byte)
+	},
+}
+
+// GetGoroutineID
+//
+//	@Description: Get returns the id of the current goroutine.
+//	@return int64
+func GetGoroutineID() int64 {
+	buf := stackBufPool.Get().(*[64]byte)
+	defer stackBufPool.Put(buf)
+	// 栈信息的第一行形如 "goroutine 123 [running]:"
+	b := buf[:runtime.Stack(buf[:], false)]
+	const prefix = "goroutine "
+	var id int64
+	for i := len(prefix); i < len(b) && b[i] >= '0' && b[i] <= '9'; i++ {
+		id = id*10 + int64(b[i]-'0')
+	}
+	if id == 0 {
+		panic("lockx: cannot parse goroutine id from " + string(b))
+	}
+	return id
+}
+
+// Owner 显式的锁持有者标识
+// @Description: 通过XxxAs系列方法加解锁时，用Owner代替goroutine id识别持有者，不需要读取栈信息，
+// 开销接近sync.Mutex，也允许在不同的goroutine之间传递锁的所有权。Owner的值都是负数，不会与goroutine id冲突
+type Owner int64
+
+// ownerSeq 最近一次分配的Owner
+var ownerSeq int64
+
+// NewOwner
+//
+//	@Description: 分配一个新的持有者标识
+//	@return Owner
+func NewOwner() Owner {
+	return Owner(atomic.AddInt64(&ownerSeq, -1))
+}
@@ -1,77 +1,225 @@
 package lockx
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"runtime"
-	"strconv"
 	"sync"
-	"sync/atomic"
+	"time"
 )
 
-type ReentrantLock struct {
-	sync.Mutex
-	recursion int32 // 这个goroutine 重入的次数
-	owner     int64 // 当前持有锁的goroutine id
+// ReentrantLock 可重入锁，与ReentrantMutex是同一个类型，保留这个名字是为了兼容
+type ReentrantLock = ReentrantMutex
+
+// NewReentrantLock
+//
+//	@Description: 创建可重入锁，零值的ReentrantMutex同样可以直接使用
+//	@return *ReentrantMutex
+func NewReentrantLock() *ReentrantMutex {
+	return &ReentrantMutex{}
+}
+
+// ReentrantMutex 包装一个Mutex,实现可重入
+type ReentrantMutex struct {
+	mu        sync.Mutex // 保护下面的字段
+	owner     int64      // 当前持有锁的goroutine id或Owner，0表示没有持有者
+	recursion int32      // 持有者重入的次数
+	released  notifier   // 锁被完全释放时通知等待者
+}
+
+// Lock
+//
+//	@Description: 加锁，同一个goroutine可以重复加锁，需要调用相同次数的Unlock才会释放
+//	@receiver m
+func (m *ReentrantMutex) Lock() {
+	_ = m.lockContext(context.Background(), GetGoroutineID())
+}
+
+// TryLock
+//
+//	@Description: 尝试加锁，不阻塞
+//	@receiver m
+//	@return bool 是否成功
+func (m *ReentrantMutex) TryLock() bool {
+	return m.tryLock(GetGoroutineID())
+}
+
+// LockTimeout
+//
+//	@Description: 加锁，最多等待d
+//	@receiver m
+//	@param d
+//	@return bool 是否成功
+func (m *ReentrantMutex) LockTimeout(d time.Duration) bool {
+	id := GetGoroutineID()
+	return withTimeout(d, func(ctx context.Context) error {
+		return m.lockContext(ctx, id)
+	})
+}
+
+// LockContext
+//
+//	@Description: 加锁，直到成功或者ctx结束
+//	@receiver m
+//	@param ctx
+//	@return error ctx结束时返回ctx.Err()
+func (m *ReentrantMutex) LockContext(ctx context.Context) error {
+	return m.lockContext(ctx, GetGoroutineID())
+}
+
+// Unlock
+//
+//	@Description: 解锁，非持有锁的goroutine调用会panic
+//	@receiver m
+func (m *ReentrantMutex) Unlock() {
+	m.unlock(GetGoroutineID())
+}
+
+// LockAs
+//
+//	@Description: 以owner的身份加锁，同一个owner可以重复加锁
+//	@receiver m
+//	@param owner
+func (m *ReentrantMutex) LockAs(owner Owner) {
+	_ = m.lockContext(context.Background(), int64(owner))
 }
 
-// GetGoroutineID
+// TryLockAs
 //
-//	@Description: Get returns the id of the current goroutine.
-//	@return int64
-func GetGoroutineID() int64 {
-	b := make([]byte, 64)
-	b = b[:runtime.Stack(b, false)]
-	b = bytes.TrimPrefix(b, []byte("goroutine "))
-	b = b[:bytes.IndexByte(b, ' ')]
-	n, err := strconv.ParseUint(string(b), 10, 64)
-	if err != nil {
-		panic(err)
+//	@Description: 以owner的身份尝试加锁，不阻塞
+//	@receiver m
+//	@param owner
+//	@return bool 是否成功
+func (m *ReentrantMutex) TryLockAs(owner Owner) bool {
+	return m.tryLock(int64(owner))
+}
+
+// LockContextAs
+//
+//	@Description: 以owner的身份加锁，直到成功或者ctx结束
+//	@receiver m
+//	@param ctx
+//	@param owner
+//	@return error ctx结束时返回ctx.Err()
+func (m *ReentrantMutex) LockContextAs(ctx context.Context, owner Owner) error {
+	return m.lockContext(ctx, int64(owner))
+}
+
+// UnlockAs
+//
+//	@Description: 以owner的身份解锁，owner不是持有者时会panic
+//	@receiver m
+//	@param owner
+func (m *ReentrantMutex) UnlockAs(owner Owner) {
+	m.unlock(int64(owner))
+}
+
+// tryLock
+//
+//	@Description:
+//	@receiver m
+//	@param id goroutine id或者Owner
+//	@return bool
+func (m *ReentrantMutex) tryLock(id int64) bool {
+	ok := m.tryLockOnce(id)
+	if d := activeDetector.Load(); ok && d != nil {
+		d.acquired(m, id)
 	}
-	return int64(n)
+	return ok
+}
+
+// tryLockOnce
+//
+//	@Description: 持有m.mu尝试一次
+//	@receiver m
+//	@param id goroutine id或者Owner
+//	@return bool
+func (m *ReentrantMutex) tryLockOnce(id int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tryLockLocked(id)
 }
 
-func NewReentrantLock() sync.Locker {
-	res := &ReentrantLock{
-		Mutex:     sync.Mutex{},
-		recursion: 0,
-		owner:     0,
+// lockContext
+//
+//	@Description:
+//	@receiver m
+//	@param ctx
+//	@param id goroutine id或者Owner
+//	@return error
+func (m *ReentrantMutex) lockContext(ctx context.Context, id int64) error {
+	d := activeDetector.Load()
+	if d == nil {
+		return m.acquire(ctx, id)
 	}
-	return res
+	stop := d.beforeLock(m, id)
+	err := m.acquire(ctx, id)
+	stop()
+	if err == nil {
+		d.acquired(m, id)
+	}
+	return err
 }
 
-// ReentrantMutex 包装一个Mutex,实现可重入
-type ReentrantMutex struct {
-	sync.Mutex
-	owner     int64 // 当前持有锁的goroutine id
-	recursion int32 // 这个goroutine 重入的次数
+// acquire
+//
+//	@Description: 等待直到加锁成功或者ctx结束
+//	@receiver m
+//	@param ctx
+//	@param id goroutine id或者Owner
+//	@return error
+func (m *ReentrantMutex) acquire(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for !m.tryLockLocked(id) {
+		if err := m.released.wait(ctx, &m.mu); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (m *ReentrantMutex) Lock() {
-	gid := GetGoroutineID()
-	// 如果当前持有锁的goroutine就是这次调用的goroutine,说明是重入
-	if atomic.LoadInt64(&m.owner) == gid {
+// tryLockLocked
+//
+//	@Description: 尝试加锁，调用时需持有m.mu
+//	@receiver m
+//	@param id
+//	@return bool
+func (m *ReentrantMutex) tryLockLocked(id int64) bool {
+	// 如果当前持有锁的就是这次调用者,说明是重入
+	if m.owner == id {
 		m.recursion++
-		return
+		return true
+	}
+	if m.owner != 0 {
+		return false
 	}
-	m.Mutex.Lock()
-	// 获得锁的goroutine第一次调用，记录下它的goroutine id,调用次数加1
-	atomic.StoreInt64(&m.owner, gid)
+	// 获得锁后第一次调用，记录下持有者,调用次数为1
+	m.owner = id
 	m.recursion = 1
+	return true
 }
 
-func (m *ReentrantMutex) Unlock() {
-	gid := GetGoroutineID()
-	// 非持有锁的goroutine尝试释放锁，错误的使用
-	if atomic.LoadInt64(&m.owner) != gid {
-		panic(fmt.Sprintf("wrong the owner(%d): %d!", m.owner, gid))
+// unlock
+//
+//	@Description:
+//	@receiver m
+//	@param id goroutine id或者Owner
+func (m *ReentrantMutex) unlock(id int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// 非持有者尝试释放锁，错误的使用
+	if m.owner != id {
+		panic(fmt.Sprintf("lockx: unlock of ReentrantMutex by %d, owner is %d", id, m.owner))
+	}
+	if d := activeDetector.Load(); d != nil {
+		d.released(m, id)
 	}
 	// 调用次数减1
 	m.recursion--
-	if m.recursion != 0 { // 如果这个goroutine还没有完全释放，则直接返回
+	if m.recursion != 0 { // 如果还没有完全释放，则直接返回
 		return
 	}
-	// 此goroutine最后一次调用，需要释放锁
-	atomic.StoreInt64(&m.owner, -1)
-	m.Mutex.Unlock()
+	// 最后一次调用，需要释放锁
+	m.owner = 0
+	m.released.broadcast()
 }
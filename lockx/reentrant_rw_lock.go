@@ -0,0 +1,440 @@
+package lockx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReentrantRWMutex 可重入的读写锁
+// @Description: 持有写锁的goroutine可以重复加写锁和读锁，持有读锁的goroutine可以重复加读锁，
+// 持有写锁时加读锁再释放写锁即完成降级；只持有读锁时加写锁（升级）会死锁，因此直接panic。
+// 有写锁在等待时新的读者会排队，避免写者饥饿，已经持有读锁的goroutine重入时不受影响。零值可以直接使用
+type ReentrantRWMutex struct {
+	mu             sync.Mutex      // 保护下面的字段
+	writer         int64           // 持有写锁的goroutine id或Owner，0表示没有
+	writes         int32           // 写锁重入的次数
+	readers        map[int64]int32 // 持有读锁的goroutine id或Owner及其重入的次数
+	waitingWriters int             // 正在等待写锁的goroutine数量
+	released       notifier        // 锁被释放或者等待的写者放弃时通知等待者
+}
+
+// NewReentrantRWMutex
+//
+//	@Description: 创建可重入读写锁
+//	@return *ReentrantRWMutex
+func NewReentrantRWMutex() *ReentrantRWMutex {
+	return &ReentrantRWMutex{}
+}
+
+// Lock
+//
+//	@Description: 加写锁
+//	@receiver m
+func (m *ReentrantRWMutex) Lock() {
+	_ = m.lockContext(context.Background(), GetGoroutineID())
+}
+
+// TryLock
+//
+//	@Description: 尝试加写锁，不阻塞
+//	@receiver m
+//	@return bool 是否成功
+func (m *ReentrantRWMutex) TryLock() bool {
+	return m.tryLock(GetGoroutineID())
+}
+
+// LockTimeout
+//
+//	@Description: 加写锁，最多等待d
+//	@receiver m
+//	@param d
+//	@return bool 是否成功
+func (m *ReentrantRWMutex) LockTimeout(d time.Duration) bool {
+	id := GetGoroutineID()
+	return withTimeout(d, func(ctx context.Context) error {
+		return m.lockContext(ctx, id)
+	})
+}
+
+// LockContext
+//
+//	@Description: 加写锁，直到成功或者ctx结束
+//	@receiver m
+//	@param ctx
+//	@return error ctx结束时返回ctx.Err()
+func (m *ReentrantRWMutex) LockContext(ctx context.Context) error {
+	return m.lockContext(ctx, GetGoroutineID())
+}
+
+// Unlock
+//
+//	@Description: 释放写锁，非持有写锁的goroutine调用会panic
+//	@receiver m
+func (m *ReentrantRWMutex) Unlock() {
+	m.unlock(GetGoroutineID())
+}
+
+// RLock
+//
+//	@Description: 加读锁
+//	@receiver m
+func (m *ReentrantRWMutex) RLock() {
+	_ = m.rlockContext(context.Background(), GetGoroutineID())
+}
+
+// TryRLock
+//
+//	@Description: 尝试加读锁，不阻塞
+//	@receiver m
+//	@return bool 是否成功
+func (m *ReentrantRWMutex) TryRLock() bool {
+	return m.tryRLock(GetGoroutineID())
+}
+
+// RLockTimeout
+//
+//	@Description: 加读锁，最多等待d
+//	@receiver m
+//	@param d
+//	@return bool 是否成功
+func (m *ReentrantRWMutex) RLockTimeout(d time.Duration) bool {
+	id := GetGoroutineID()
+	return withTimeout(d, func(ctx context.Context) error {
+		return m.rlockContext(ctx, id)
+	})
+}
+
+// RLockContext
+//
+//	@Description: 加读锁，直到成功或者ctx结束
+//	@receiver m
+//	@param ctx
+//	@return error ctx结束时返回ctx.Err()
+func (m *ReentrantRWMutex) RLockContext(ctx context.Context) error {
+	return m.rlockContext(ctx, GetGoroutineID())
+}
+
+// RUnlock
+//
+//	@Description: 释放读锁，非持有读锁的goroutine调用会panic
+//	@receiver m
+func (m *ReentrantRWMutex) RUnlock() {
+	m.runlock(GetGoroutineID())
+}
+
+// LockAs
+//
+//	@Description: 以owner的身份加写锁
+//	@receiver m
+//	@param owner
+func (m *ReentrantRWMutex) LockAs(owner Owner) {
+	_ = m.lockContext(context.Background(), int64(owner))
+}
+
+// TryLockAs
+//
+//	@Description: 以owner的身份尝试加写锁，不阻塞
+//	@receiver m
+//	@param owner
+//	@return bool 是否成功
+func (m *ReentrantRWMutex) TryLockAs(owner Owner) bool {
+	return m.tryLock(int64(owner))
+}
+
+// LockContextAs
+//
+//	@Description: 以owner的身份加写锁，直到成功或者ctx结束
+//	@receiver m
+//	@param ctx
+//	@param owner
+//	@return error ctx结束时返回ctx.Err()
+func (m *ReentrantRWMutex) LockContextAs(ctx context.Context, owner Owner) error {
+	return m.lockContext(ctx, int64(owner))
+}
+
+// UnlockAs
+//
+//	@Description: 以owner的身份释放写锁
+//	@receiver m
+//	@param owner
+func (m *ReentrantRWMutex) UnlockAs(owner Owner) {
+	m.unlock(int64(owner))
+}
+
+// RLockAs
+//
+//	@Description: 以owner的身份加读锁
+//	@receiver m
+//	@param owner
+func (m *ReentrantRWMutex) RLockAs(owner Owner) {
+	_ = m.rlockContext(context.Background(), int64(owner))
+}
+
+// TryRLockAs
+//
+//	@Description: 以owner的身份尝试加读锁，不阻塞
+//	@receiver m
+//	@param owner
+//	@return bool 是否成功
+func (m *ReentrantRWMutex) TryRLockAs(owner Owner) bool {
+	return m.tryRLock(int64(owner))
+}
+
+// RLockContextAs
+//
+//	@Description: 以owner的身份加读锁，直到成功或者ctx结束
+//	@receiver m
+//	@param ctx
+//	@param owner
+//	@return error ctx结束时返回ctx.Err()
+func (m *ReentrantRWMutex) RLockContextAs(ctx context.Context, owner Owner) error {
+	return m.rlockContext(ctx, int64(owner))
+}
+
+// RUnlockAs
+//
+//	@Description: 以owner的身份释放读锁
+//	@receiver m
+//	@param owner
+func (m *ReentrantRWMutex) RUnlockAs(owner Owner) {
+	m.runlock(int64(owner))
+}
+
+// RLocker
+//
+//	@Description: 返回使用读锁的sync.Locker
+//	@receiver m
+//	@return sync.Locker
+func (m *ReentrantRWMutex) RLocker() sync.Locker {
+	return (*reentrantRLocker)(m)
+}
+
+// tryLock
+//
+//	@Description:
+//	@receiver m
+//	@param id goroutine id或者Owner
+//	@return bool
+func (m *ReentrantRWMutex) tryLock(id int64) bool {
+	ok := m.tryLockOnce(id)
+	if d := activeDetector.Load(); ok && d != nil {
+		d.acquired(m, id)
+	}
+	return ok
+}
+
+// tryLockOnce
+//
+//	@Description: 持有m.mu尝试一次，从读锁升级时会panic，用defer保证m.mu被释放
+//	@receiver m
+//	@param id goroutine id或者Owner
+//	@return bool
+func (m *ReentrantRWMutex) tryLockOnce(id int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tryLockLocked(id)
+}
+
+// lockContext
+//
+//	@Description:
+//	@receiver m
+//	@param ctx
+//	@param id goroutine id或者Owner
+//	@return error
+func (m *ReentrantRWMutex) lockContext(ctx context.Context, id int64) error {
+	d := activeDetector.Load()
+	if d == nil {
+		return m.acquire(ctx, id)
+	}
+	stop := d.beforeLock(m, id)
+	err := m.acquire(ctx, id)
+	stop()
+	if err == nil {
+		d.acquired(m, id)
+	}
+	return err
+}
+
+// acquire
+//
+//	@Description: 等待直到加写锁成功或者ctx结束
+//	@receiver m
+//	@param ctx
+//	@param id goroutine id或者Owner
+//	@return error
+func (m *ReentrantRWMutex) acquire(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for !m.tryLockLocked(id) {
+		m.waitingWriters++
+		err := m.released.wait(ctx, &m.mu)
+		m.waitingWriters--
+		if err != nil {
+			// 放弃等待后，被这个写者挡住的读者可能可以继续了
+			if m.waitingWriters == 0 {
+				m.released.broadcast()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// unlock
+//
+//	@Description:
+//	@receiver m
+//	@param id goroutine id或者Owner
+func (m *ReentrantRWMutex) unlock(id int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.writer != id {
+		panic(fmt.Sprintf("lockx: unlock of ReentrantRWMutex by %d, writer is %d", id, m.writer))
+	}
+	if d := activeDetector.Load(); d != nil {
+		d.released(m, id)
+	}
+	m.writes--
+	if m.writes == 0 {
+		m.writer = 0
+		m.released.broadcast()
+	}
+}
+
+// tryRLock
+//
+//	@Description:
+//	@receiver m
+//	@param id goroutine id或者Owner
+//	@return bool
+func (m *ReentrantRWMutex) tryRLock(id int64) bool {
+	ok := m.tryRLockOnce(id)
+	if d := activeDetector.Load(); ok && d != nil {
+		d.acquired(m, id)
+	}
+	return ok
+}
+
+// tryRLockOnce
+//
+//	@Description: 持有m.mu尝试一次
+//	@receiver m
+//	@param id goroutine id或者Owner
+//	@return bool
+func (m *ReentrantRWMutex) tryRLockOnce(id int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tryRLockLocked(id)
+}
+
+// rlockContext
+//
+//	@Description:
+//	@receiver m
+//	@param ctx
+//	@param id goroutine id或者Owner
+//	@return error
+func (m *ReentrantRWMutex) rlockContext(ctx context.Context, id int64) error {
+	d := activeDetector.Load()
+	if d == nil {
+		return m.racquire(ctx, id)
+	}
+	stop := d.beforeLock(m, id)
+	err := m.racquire(ctx, id)
+	stop()
+	if err == nil {
+		d.acquired(m, id)
+	}
+	return err
+}
+
+// racquire
+//
+//	@Description: 等待直到加读锁成功或者ctx结束
+//	@receiver m
+//	@param ctx
+//	@param id goroutine id或者Owner
+//	@return error
+func (m *ReentrantRWMutex) racquire(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for !m.tryRLockLocked(id) {
+		if err := m.released.wait(ctx, &m.mu); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runlock
+//
+//	@Description:
+//	@receiver m
+//	@param id goroutine id或者Owner
+func (m *ReentrantRWMutex) runlock(id int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.readers[id]
+	if !ok {
+		panic(fmt.Sprintf("lockx: runlock of ReentrantRWMutex by %d without read lock", id))
+	}
+	if d := activeDetector.Load(); d != nil {
+		d.released(m, id)
+	}
+	if n > 1 {
+		m.readers[id] = n - 1
+		return
+	}
+	delete(m.readers, id)
+	if len(m.readers) == 0 {
+		m.released.broadcast()
+	}
+}
+
+// tryLockLocked
+//
+//	@Description: 尝试加写锁，调用时需持有m.mu
+//	@receiver m
+//	@param id
+//	@return bool
+func (m *ReentrantRWMutex) tryLockLocked(id int64) bool {
+	if m.writer == id {
+		m.writes++
+		return true
+	}
+	if _, ok := m.readers[id]; ok && m.writer == 0 {
+		panic(fmt.Sprintf("lockx: %d cannot upgrade ReentrantRWMutex from read to write lock", id))
+	}
+	if m.writer != 0 || len(m.readers) > 0 {
+		return false
+	}
+	m.writer = id
+	m.writes = 1
+	return true
+}
+
+// tryRLockLocked
+//
+//	@Description: 尝试加读锁，调用时需持有m.mu
+//	@receiver m
+//	@param id
+//	@return bool
+func (m *ReentrantRWMutex) tryRLockLocked(id int64) bool {
+	n, reentrant := m.readers[id]
+	if !reentrant && m.writer != id && (m.writer != 0 || m.waitingWriters > 0) {
+		return false
+	}
+	if m.readers == nil {
+		m.readers = make(map[int64]int32)
+	}
+	m.readers[id] = n + 1
+	return true
+}
+
+type reentrantRLocker ReentrantRWMutex
+
+func (r *reentrantRLocker) Lock()   { (*ReentrantRWMutex)(r).RLock() }
+func (r *reentrantRLocker) Unlock() { (*ReentrantRWMutex)(r).RUnlock() }
@@ -0,0 +1,260 @@
+package lockx
+
+import (
+	"context"
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"sync"
+)
+
+// Semaphore 带权重的信号量
+// @Description: 等待者按先来先得的顺序获得许可，队首的等待者许可不够时后面的等待者也不会插队，避免大请求饿死；
+// 许可总数可以在运行时调整
+type Semaphore struct {
+	mu      sync.Mutex
+	size    int64                            // 许可总数
+	cur     int64                            // 已经发出的许可数
+	waiters containerx.List[semaphoreWaiter] // 等待者队列
+}
+
+type semaphoreWaiter struct {
+	n     int64
+	ready chan struct{} // 获得许可后close
+}
+
+// NewSemaphore
+//
+//	@Description: 创建信号量
+//	@param n 许可总数
+//	@return *Semaphore
+func NewSemaphore(n int64) *Semaphore {
+	return &Semaphore{size: n}
+}
+
+// Acquire
+//
+//	@Description: 获取n个许可，直到成功或者ctx结束。n大于许可总数时会一直等待到Resize扩容或者ctx结束
+//	@receiver s
+//	@param ctx
+//	@param n 为0时立即成功，小于0时panic
+//	@return error ctx结束时返回ctx.Err()，此时不持有任何许可
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	if checkWeight(n) {
+		return nil
+	}
+	s.mu.Lock()
+	if s.waiters.Len() == 0 && s.size-s.cur >= n {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(semaphoreWaiter{n: n, ready: ready})
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-ready:
+			// 取消的同时已经获得了许可，归还后按失败处理
+			s.cur -= n
+		default:
+			s.waiters.Remove(elem)
+		}
+		// 无论哪种情况，后面的等待者都可能可以继续了
+		s.notifyWaiters()
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire
+//
+//	@Description: 尝试获取n个许可，不阻塞，有等待者时直接失败
+//	@receiver s
+//	@param n 为0时立即成功，小于0时panic
+//	@return bool 是否成功
+func (s *Semaphore) TryAcquire(n int64) bool {
+	if checkWeight(n) {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.waiters.Len() == 0 && s.size-s.cur >= n {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release
+//
+//	@Description: 归还n个许可，归还的比持有的多时会panic
+//	@receiver s
+//	@param n 为0时什么都不做，小于0时panic
+func (s *Semaphore) Release(n int64) {
+	if checkWeight(n) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("lockx: semaphore released more than held")
+	}
+	s.notifyWaiters()
+}
+
+// Resize
+//
+//	@Description: 调整许可总数，缩容时已经发出的许可不受影响，归还后才会生效
+//	@receiver s
+//	@param n
+func (s *Semaphore) Resize(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.size = n
+	s.notifyWaiters()
+}
+
+// Size
+//
+//	@Description: 许可总数
+//	@receiver s
+//	@return int64
+func (s *Semaphore) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// Available
+//
+//	@Description: 当前可用的许可数，缩容后可能为负数
+//	@receiver s
+//	@return int64
+func (s *Semaphore) Available() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size - s.cur
+}
+
+// Waiting
+//
+//	@Description: 正在等待的数量
+//	@receiver s
+//	@return int
+func (s *Semaphore) Waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.waiters.Len()
+}
+
+// checkWeight
+//
+//	@Description: 检查许可数，负数会让计数错乱，直接panic
+//	@param n
+//	@return bool n是否为0，为0时不需要改变任何状态
+func checkWeight(n int64) bool {
+	if n < 0 {
+		panic("lockx: semaphore weight must not be negative")
+	}
+	return n == 0
+}
+
+// notifyWaiters
+//
+//	@Description: 按顺序唤醒许可足够的等待者，调用时需持有s.mu
+//	@receiver s
+func (s *Semaphore) notifyWaiters() {
+	for {
+		front := s.waiters.Front()
+		if front == nil || s.size-s.cur < front.Value.n {
+			return
+		}
+		s.cur += front.Value.n
+		s.waiters.Remove(front)
+		close(front.Value.ready)
+	}
+}
+
+// Limiter 限制同时运行的goroutine数量
+type Limiter struct {
+	sem *Semaphore
+	wg  sync.WaitGroup
+}
+
+// NewLimiter
+//
+//	@Description: 创建并发限制器
+//	@param n 最多同时运行的goroutine数量
+//	@return *Limiter
+func NewLimiter(n int) *Limiter {
+	return &Limiter{sem: NewSemaphore(int64(n))}
+}
+
+// Go
+//
+//	@Description: 等待空闲的名额后在新的goroutine中运行fx
+//	@receiver l
+//	@param ctx
+//	@param fx
+//	@return error ctx在获得名额前结束时返回ctx.Err()，fx不会被运行
+func (l *Limiter) Go(ctx context.Context, fx func()) error {
+	if err := l.sem.Acquire(ctx, 1); err != nil {
+		return err
+	}
+	l.start(fx)
+	return nil
+}
+
+// TryGo
+//
+//	@Description: 有空闲的名额时在新的goroutine中运行fx，不阻塞
+//	@receiver l
+//	@param fx
+//	@return bool fx是否被运行
+func (l *Limiter) TryGo(fx func()) bool {
+	if !l.sem.TryAcquire(1) {
+		return false
+	}
+	l.start(fx)
+	return true
+}
+
+// Wait
+//
+//	@Description: 等待所有通过Go和TryGo启动的goroutine结束
+//	@receiver l
+func (l *Limiter) Wait() {
+	l.wg.Wait()
+}
+
+// Resize
+//
+//	@Description: 调整最多同时运行的goroutine数量
+//	@receiver l
+//	@param n
+func (l *Limiter) Resize(n int) {
+	l.sem.Resize(int64(n))
+}
+
+// start
+//
+//	@Description: 已经获得名额后启动fx，结束时归还名额
+//	@receiver l
+//	@param fx
+func (l *Limiter) start(fx func()) {
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		defer l.sem.Release(1)
+		fx()
+	}()
+}
@@ -3,6 +3,7 @@ package netx
 import (
 	"encoding/binary"
 	"errors"
+	"io"
 	"net"
 	"strings"
 )
@@ -188,7 +189,7 @@ func (p *DataPack) PackMessage(message IMessage) error {
 
 // UnPackMessage
 //
-//	@Description:
+//	@Description: 读取一个完整的消息，各字段用io.ReadFull读满，避免TCP拆包时读到不完整的数据
 //	@receiver p
 //	@Author yuhao <154826195@qq.com>
 //	@Data 2022-11-01 12:32:00
@@ -201,7 +202,7 @@ func (p *DataPack) UnPackMessage() (IMessage, error) {
 	}
 	if message.protocLen > 0 {
 		message.protoc = make([]byte, message.protocLen)
-		if _, err := p.Read(message.protoc); err != nil {
+		if _, err := io.ReadFull(p, message.protoc); err != nil {
 			return nil, err
 		}
 	}
@@ -211,7 +212,7 @@ func (p *DataPack) UnPackMessage() (IMessage, error) {
 	}
 	message.version = make([]byte, message.versionLen)
 	if message.versionLen > 0 {
-		if _, err := p.Read(message.version); err != nil {
+		if _, err := io.ReadFull(p, message.version); err != nil {
 			return nil, err
 		}
 	}
@@ -221,7 +222,7 @@ func (p *DataPack) UnPackMessage() (IMessage, error) {
 	}
 	if message.commandLen > 0 {
 		message.command = make([]byte, message.commandLen)
-		if _, err := p.Read(message.command); err != nil {
+		if _, err := io.ReadFull(p, message.command); err != nil {
 			return nil, err
 		}
 	}
@@ -234,7 +235,7 @@ func (p *DataPack) UnPackMessage() (IMessage, error) {
 	}
 	if message.bodyLen > 0 {
 		message.body = make([]byte, message.bodyLen)
-		if _, err := p.Read(message.body); err != nil {
+		if _, err := io.ReadFull(p, message.body); err != nil {
 			return nil, err
 		}
 	}
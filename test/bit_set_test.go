@@ -0,0 +1,70 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"reflect"
+	"testing"
+)
+
+func TestBitSet(t *testing.T) {
+	b := containerx.NewBitSetOf(1, 3, 64, 200)
+	if !b.Test(64) || b.Test(2) || b.Test(1000) || b.Count() != 4 {
+		t.Fatalf("Count = %d", b.Count())
+	}
+	b.Flip(3).Flip(5).Clear(200)
+	if !reflect.DeepEqual(b.Indexes(), []uint{1, 5, 64}) {
+		t.Fatalf("Indexes = %v", b.Indexes())
+	}
+	if i, ok := b.NextSet(6); !ok || i != 64 {
+		t.Fatalf("NextSet(6) = %d,%v", i, ok)
+	}
+	if _, ok := b.NextSet(65); ok {
+		t.Fatal("NextSet(65) should fail")
+	}
+
+	other := containerx.NewBitSetOf(5, 64, 130)
+	check := func(name string, got *containerx.BitSet, want ...uint) {
+		if !got.Equal(containerx.NewBitSetOf(want...)) {
+			t.Fatalf("%s = %v, want %v", name, got.Indexes(), want)
+		}
+	}
+	check("And", b.And(other), 5, 64)
+	check("Or", b.Or(other), 1, 5, 64, 130)
+	check("Xor", b.Xor(other), 1, 130)
+	check("AndNot", b.AndNot(other), 1)
+
+	if !containerx.BitSetFromSet(b.ToSet()).Equal(b) {
+		t.Fatal("Set conversion round trip failed")
+	}
+
+	data, _ := b.MarshalBinary()
+	var fromBinary containerx.BitSet
+	if err := fromBinary.UnmarshalBinary(data); err != nil || !fromBinary.Equal(b) {
+		t.Fatalf("binary round trip failed: %v", err)
+	}
+	data, _ = json.Marshal(b)
+	if string(data) != "[1,5,64]" {
+		t.Fatalf("json = %s", data)
+	}
+	var s containerx.Set[uint]
+	if err := json.Unmarshal(data, &s); err != nil || !s.Equal(b.ToSet()) {
+		t.Fatalf("json should be readable as Set[uint]: %v", err)
+	}
+}
+
+func TestBitSetIndexLimit(t *testing.T) {
+	for _, data := range []string{`[18446744073709551615]`, `[4000000000000]`, `[1, 4294967296]`} {
+		var b containerx.BitSet
+		if err := json.Unmarshal([]byte(data), &b); err != containerx.ErrBitSetIndexOutOfRange {
+			t.Fatalf("Unmarshal(%s) = %v", data, err)
+		}
+	}
+	var b containerx.BitSet
+	defer func() {
+		if recover() != containerx.ErrBitSetIndexOutOfRange {
+			t.Fatal("Set above MaxBitSetIndex should panic")
+		}
+	}()
+	b.Set(containerx.MaxBitSetIndex + 1)
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/yuhao-jack/go-toolx/containerx/bloom"
+	"io"
+	"math"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestBloomFalsePositiveRate(t *testing.T) {
+	f := bloom.NewWithEstimates(10000, 0.01)
+	for i := 0; i < 10000; i++ {
+		f.AddString(strconv.Itoa(i))
+	}
+	for i := 0; i < 10000; i++ {
+		if !f.TestString(strconv.Itoa(i)) {
+			t.Fatalf("%d should be present", i)
+		}
+	}
+	fp := 0
+	for i := 10000; i < 20000; i++ {
+		if f.TestString(strconv.Itoa(i)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / 10000; rate > 0.02 {
+		t.Fatalf("false positive rate = %v", rate)
+	}
+	if n := f.EstimateCount(); n < 9500 || n > 10500 {
+		t.Fatalf("EstimateCount = %d", n)
+	}
+}
+
+func TestBloomUnionAndSerialize(t *testing.T) {
+	a := bloom.New(1024, 3).AddString("a")
+	b := bloom.New(1024, 3).AddString("b")
+	if err := a.Union(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Union(bloom.New(512, 3)); err != bloom.ErrIncompatible {
+		t.Fatalf("Union = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := a.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var c bloom.Filter
+	if _, err := c.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !c.TestString("a") || !c.TestString("b") || c.Cap() != 1024 || c.K() != 3 {
+		t.Fatal("filter mismatch after ReadFrom")
+	}
+	if err := c.UnmarshalBinary([]byte{1, 2, 3}); err != bloom.ErrInvalidData {
+		t.Fatalf("UnmarshalBinary = %v", err)
+	}
+}
+
+func TestCountingBloom(t *testing.T) {
+	f := bloom.NewCountingWithEstimates(100, 0.01)
+	f.AddString("a").AddString("a").AddString("b")
+	if !f.RemoveString("a") || !f.TestString("a") {
+		t.Fatal("a was added twice and should survive one Remove")
+	}
+	f.RemoveString("a")
+	if f.TestString("a") || !f.TestString("b") {
+		t.Fatal("a should be removed and b should stay")
+	}
+	if f.RemoveString("missing") {
+		t.Fatal("Remove of a missing element should return false")
+	}
+
+	data, _ := f.MarshalBinary()
+	var g bloom.CountingFilter
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !g.ToFilter().TestString("b") {
+		t.Fatal("b should be present after round trip")
+	}
+}
+
+// bloomHeader 构造只有头部的序列化数据
+func bloomHeader(m, k uint64, body int) []byte {
+	data := make([]byte, 16+body)
+	binary.BigEndian.PutUint64(data[0:], m)
+	binary.BigEndian.PutUint64(data[8:], k)
+	return data
+}
+
+func TestBloomUnmarshalCorrupt(t *testing.T) {
+	for _, data := range [][]byte{
+		bloomHeader(1<<64-1, 1, 0), // (m+63)/64溢出为0
+		bloomHeader(8, 1000, 8),    // k超过上限，其余字段对两种过滤器都合法
+		bloomHeader(64, 0, 8),
+		bloomHeader(0, 1, 0),
+		bloomHeader(128, 1, 8),
+	} {
+		var f bloom.Filter
+		if err := f.UnmarshalBinary(data); err != bloom.ErrInvalidData {
+			t.Fatalf("Filter.UnmarshalBinary(%x) = %v", data[:16], err)
+		}
+		var c bloom.CountingFilter
+		if err := c.UnmarshalBinary(data); err != bloom.ErrInvalidData {
+			t.Fatalf("CountingFilter.UnmarshalBinary(%x) = %v", data[:16], err)
+		}
+	}
+	if f := bloom.New(64, 1000); f.K() != 255 {
+		t.Fatalf("K = %d", f.K())
+	}
+}
+
+func TestBloomReadFromTruncated(t *testing.T) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	// 头部声称有很大的位数组，实际只有8个字节
+	var f bloom.Filter
+	if _, err := f.ReadFrom(bytes.NewReader(bloomHeader(math.MaxInt32*64, 3, 8))); err != io.ErrUnexpectedEOF {
+		t.Fatalf("Filter.ReadFrom = %v", err)
+	}
+	var c bloom.CountingFilter
+	if _, err := c.ReadFrom(bytes.NewReader(bloomHeader(math.MaxInt32, 3, 8))); err != io.ErrUnexpectedEOF {
+		t.Fatalf("CountingFilter.ReadFrom = %v", err)
+	}
+	if _, err := c.ReadFrom(bytes.NewReader(bloomHeader(8, 1000, 8))); err != bloom.ErrInvalidData {
+		t.Fatalf("CountingFilter.ReadFrom = %v", err)
+	}
+	runtime.ReadMemStats(&after)
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 1<<20 {
+		t.Fatalf("ReadFrom allocated %d bytes for a truncated stream", alloc)
+	}
+}
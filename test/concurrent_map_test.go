@@ -0,0 +1,126 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentMapCompute(t *testing.T) {
+	m := containerx.NewConcurrentMap[string, int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Compute("counter", func(old int, exists bool) (int, bool) {
+					return old + 1, true
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := m.Get("counter"); v != 10000 {
+		t.Fatalf("counter = %d, want 10000", v)
+	}
+
+	if _, ok := m.Compute("counter", func(old int, exists bool) (int, bool) { return 0, false }); ok {
+		t.Fatal("Compute with keep=false should remove the key")
+	}
+	if _, ok := m.Get("counter"); ok {
+		t.Fatal("counter should be removed")
+	}
+}
+
+func TestConcurrentMapCompareAndSwap(t *testing.T) {
+	m := containerx.NewConcurrentMap[string, int]()
+	if actual, loaded := m.GetOrSet("a", 1); loaded || actual != 1 {
+		t.Fatalf("GetOrSet = %d,%v", actual, loaded)
+	}
+	if actual, loaded := m.GetOrSet("a", 2); !loaded || actual != 1 {
+		t.Fatalf("GetOrSet = %d,%v", actual, loaded)
+	}
+	if m.SetIfAbsent("a", 3) {
+		t.Fatal("SetIfAbsent should fail for an existing key")
+	}
+	if m.CompareAndSwap("a", 2, 3) {
+		t.Fatal("CompareAndSwap should fail when old does not match")
+	}
+	if !m.CompareAndSwap("a", 1, 3) {
+		t.Fatal("CompareAndSwap should succeed")
+	}
+	if m.CompareAndDelete("a", 1) {
+		t.Fatal("CompareAndDelete should fail when old does not match")
+	}
+	if m.RemoveIf("a", func(val int) bool { return val > 3 }) {
+		t.Fatal("RemoveIf should not remove")
+	}
+	if v, ok := m.Pop("a"); !ok || v != 3 {
+		t.Fatalf("Pop = %d,%v", v, ok)
+	}
+	if _, ok := m.Pop("a"); ok {
+		t.Fatal("Pop on a missing key should return false")
+	}
+}
+
+func TestConcurrentMapShards(t *testing.T) {
+	for _, c := range []struct{ in, want int }{{0, 1}, {1, 1}, {3, 4}, {8, 8}, {9, 16}} {
+		if got := containerx.NewConcurrentMapWithShards[int, int](c.in).ShardCount(); got != c.want {
+			t.Fatalf("NewConcurrentMapWithShards(%d).ShardCount() = %d, want %d", c.in, got, c.want)
+		}
+	}
+
+	old := containerx.ShardCount
+	defer func() { containerx.ShardCount = old }()
+	m := containerx.NewConcurrentMap[int, int](containerx.WithShardCapacity(16))
+	for i := 0; i < 1000; i++ {
+		m.Set(i, i)
+	}
+	containerx.ShardCount = old*2 + 1
+	for i := 0; i < 1000; i++ {
+		if v, ok := m.Get(i); !ok || v != i {
+			t.Fatalf("Get(%d) = %d,%v after changing ShardCount", i, v, ok)
+		}
+	}
+}
+
+func TestConcurrentMapRange(t *testing.T) {
+	m := containerx.NewConcurrentMapWithShards[string, int](4)
+	m.MSet(map[string]int{"a": 1, "b": 2, "c": 3})
+	if m.Len() != 3 || len(m.Keys()) != 3 || len(m.Values()) != 3 {
+		t.Fatalf("Len = %d", m.Len())
+	}
+	// 回调中修改同一个map不应死锁
+	m.Each(func(key string, val int) {
+		m.Set(key, val*10)
+	})
+	if got := m.MGet("a", "b", "x"); len(got) != 2 || got["a"] != 10 || got["b"] != 20 {
+		t.Fatalf("MGet = %v", got)
+	}
+	count := 0
+	m.Range(func(key string, val int) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Fatalf("Range visited %d items after stop", count)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m2 containerx.ConcurrentMap[string, int]
+	if err = json.Unmarshal(data, &m2); err != nil {
+		t.Fatal(err)
+	}
+	if items := m2.Items(); len(items) != 3 || items["c"] != 30 {
+		t.Fatalf("Items after unmarshal = %v", items)
+	}
+	m2.Clear()
+	if m2.Len() != 0 {
+		t.Fatalf("Len after Clear = %d", m2.Len())
+	}
+}
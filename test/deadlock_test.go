@@ -0,0 +1,152 @@
+package test
+
+import (
+	"github.com/yuhao-jack/go-toolx/lockx"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collectDeadlocks 开启死锁检测并收集报告，测试结束时关闭
+func collectDeadlocks(t *testing.T, opts ...lockx.DeadlockOption) func() []lockx.DeadlockReport {
+	var (
+		mu      sync.Mutex
+		reports []lockx.DeadlockReport
+	)
+	opts = append(opts, lockx.WithDeadlockHandler(func(r lockx.DeadlockReport) {
+		mu.Lock()
+		defer mu.Unlock()
+		reports = append(reports, r)
+	}))
+	lockx.EnableDeadlockDetection(opts...)
+	t.Cleanup(lockx.DisableDeadlockDetection)
+	return func() []lockx.DeadlockReport {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]lockx.DeadlockReport(nil), reports...)
+	}
+}
+
+func lockInOrder(locks ...sync.Locker) {
+	for _, l := range locks {
+		l.Lock()
+	}
+	for i := len(locks) - 1; i >= 0; i-- {
+		locks[i].Unlock()
+	}
+}
+
+func TestDeadlockOrderInversion(t *testing.T) {
+	reports := collectDeadlocks(t, lockx.WithWaitThreshold(0))
+	a, b := lockx.NewReentrantLock(), lockx.NewReentrantRWMutex()
+
+	lockInOrder(a, b)
+	lockInOrder(a, b)
+	if len(reports()) != 0 {
+		t.Fatalf("consistent order should not be reported: %v", reports())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lockInOrder(b, a)
+	}()
+	<-done
+	rs := reports()
+	if len(rs) != 1 || rs[0].Kind != lockx.LockOrderInversion {
+		t.Fatalf("reports=%v", rs)
+	}
+	r := rs[0]
+	if len(r.Held) != 1 || len(r.Cycle) != 1 || !strings.Contains(r.Lock, "ReentrantMutex") {
+		t.Fatalf("report=%+v", r)
+	}
+	if !strings.Contains(r.Cycle[0].Stack, "lockInOrder") || !strings.Contains(r.Stack, "lockInOrder") {
+		t.Fatalf("stacks should point to the callers:\n%s", r)
+	}
+}
+
+func TestDeadlockLongCycle(t *testing.T) {
+	reports := collectDeadlocks(t, lockx.WithWaitThreshold(0))
+	a, b, c := lockx.NewReentrantLock(), lockx.NewReentrantLock(), lockx.NewReentrantLock()
+	lockInOrder(a, b)
+	lockInOrder(b, c)
+	if len(reports()) != 0 {
+		t.Fatalf("reports=%v", reports())
+	}
+	lockInOrder(c, a)
+	rs := reports()
+	if len(rs) != 1 || len(rs[0].Cycle) != 2 {
+		t.Fatalf("reports=%v", rs)
+	}
+}
+
+func TestDeadlockReentryNotReported(t *testing.T) {
+	reports := collectDeadlocks(t, lockx.WithWaitThreshold(0))
+	a, b := lockx.NewReentrantLock(), lockx.NewReentrantRWMutex()
+	a.Lock()
+	b.Lock()
+	b.RLock()
+	a.Lock()
+	b.Unlock()
+	b.RUnlock()
+	a.Unlock()
+	a.Unlock()
+	if len(reports()) != 0 {
+		t.Fatalf("reports=%v", reports())
+	}
+	// a已经完全释放，按b->a的顺序加锁才是反序
+	lockInOrder(b, a)
+	if len(reports()) != 1 {
+		t.Fatalf("reports=%v", reports())
+	}
+}
+
+func holdForDeadlockTest(l sync.Locker, locked chan<- struct{}, release <-chan struct{}) {
+	l.Lock()
+	close(locked)
+	<-release
+	l.Unlock()
+}
+
+func TestDeadlockLongWait(t *testing.T) {
+	reports := collectDeadlocks(t, lockx.WithWaitThreshold(20*time.Millisecond))
+	m := lockx.NewReentrantLock()
+	locked, release := make(chan struct{}), make(chan struct{})
+	go holdForDeadlockTest(m, locked, release)
+	<-locked
+	if m.LockTimeout(60 * time.Millisecond) {
+		t.Fatal("lock should be held")
+	}
+	close(release)
+
+	var r lockx.DeadlockReport
+	for _, rep := range reports() {
+		if rep.Kind == lockx.LongWait {
+			r = rep
+		}
+	}
+	if r.Kind != lockx.LongWait || r.Waited < 20*time.Millisecond {
+		t.Fatalf("reports=%v", reports())
+	}
+	if len(r.Holders) != 1 || !strings.Contains(r.Holders[0].Stack, "holdForDeadlockTest") {
+		t.Fatalf("report should contain the holder:\n%s", r)
+	}
+	if !strings.Contains(r.String(), "long wait") {
+		t.Fatalf("string=%s", r)
+	}
+}
+
+func TestDeadlockDisabled(t *testing.T) {
+	reports := collectDeadlocks(t)
+	lockx.DisableDeadlockDetection()
+	if lockx.DeadlockDetectionEnabled() {
+		t.Fatal("detection should be disabled")
+	}
+	a, b := lockx.NewReentrantLock(), lockx.NewReentrantLock()
+	lockInOrder(a, b)
+	lockInOrder(b, a)
+	if len(reports()) != 0 {
+		t.Fatalf("reports=%v", reports())
+	}
+}
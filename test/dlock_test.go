@@ -0,0 +1,278 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/yuhao-jack/go-toolx/lockx/dlock"
+	"github.com/yuhao-jack/go-toolx/netx"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startLockServer 在随机端口启动锁服务，测试结束时关闭
+func startLockServer(t *testing.T) (*dlock.Server, string) {
+	t.Helper()
+	listener, err := netx.CreateTCPListener("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := dlock.NewServer()
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(func() { _ = srv.Close() })
+	return srv, listener.Addr().String()
+}
+
+func dialLockServer(t *testing.T, addr string) *dlock.Client {
+	t.Helper()
+	c, err := dlock.Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestDLockMutualExclusion(t *testing.T) {
+	_, addr := startLockServer(t)
+	var counter, inside int32
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		c := dialLockServer(t, addr)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l := c.NewLocker("counter", dlock.WithPollInterval(time.Millisecond))
+			for j := 0; j < 20; j++ {
+				l.Lock()
+				if atomic.AddInt32(&inside, 1) != 1 {
+					t.Error("two clients hold the lock at the same time")
+				}
+				counter++
+				atomic.AddInt32(&inside, -1)
+				l.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if counter != 80 {
+		t.Fatalf("counter=%d", counter)
+	}
+}
+
+func TestDLockFencingToken(t *testing.T) {
+	srv, addr := startLockServer(t)
+	a := dialLockServer(t, addr).NewLocker("job")
+	b := dialLockServer(t, addr).NewLocker("job")
+
+	if !a.TryLock() {
+		t.Fatal("a should acquire a free lock")
+	}
+	first := a.Token()
+	if token, ok := srv.Holder("job"); !ok || token != first {
+		t.Fatalf("holder=%d,%v token=%d", token, ok, first)
+	}
+	if err := a.LockContext(context.Background()); !errors.Is(err, dlock.ErrLockerHeld) {
+		t.Fatalf("err=%v", err)
+	}
+	if b.TryLock() || b.LockTimeout(20*time.Millisecond) {
+		t.Fatal("b should not acquire while a holds the lock")
+	}
+	if err := a.UnlockContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.UnlockContext(context.Background()); !errors.Is(err, dlock.ErrNotHeld) {
+		t.Fatalf("err=%v", err)
+	}
+
+	if !b.TryLock() {
+		t.Fatal("b should acquire after a unlocked")
+	}
+	defer b.Unlock()
+	if b.Token() <= first {
+		t.Fatalf("token %d should be greater than %d", b.Token(), first)
+	}
+}
+
+func TestDLockForeignToken(t *testing.T) {
+	srv, addr := startLockServer(t)
+	a := dialLockServer(t, addr).NewLocker("owned")
+	a.Lock()
+	defer a.Unlock()
+
+	// B直接使用协议，拿着A的token尝试解锁和续租
+	conn, err := netx.CreateTcpConn(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	pack := &netx.DataPack{Conn: conn}
+	for _, command := range []string{dlock.CmdUnlock, dlock.CmdRenew} {
+		body, _ := json.Marshal(map[string]any{"id": 1, "key": "owned", "token": a.Token()})
+		if err = pack.Pack([]byte(command), body); err != nil {
+			t.Fatal(err)
+		}
+		msg, err := pack.UnPackMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var resp struct {
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+		}
+		if err = json.Unmarshal(msg.GetBody(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.OK || resp.Error != dlock.ErrNotHeld.Error() {
+			t.Fatalf("%s with a foreign token: %+v", command, resp)
+		}
+	}
+	if token, ok := srv.Holder("owned"); !ok || token != a.Token() {
+		t.Fatalf("holder=%d,%v a=%d", token, ok, a.Token())
+	}
+}
+
+func TestDLockRenew(t *testing.T) {
+	_, addr := startLockServer(t)
+	a := dialLockServer(t, addr).NewLocker("renew", dlock.WithTTL(60*time.Millisecond))
+	b := dialLockServer(t, addr).NewLocker("renew")
+
+	a.Lock()
+	// 持有超过多个租约周期，续租让锁一直有效
+	if b.LockTimeout(200 * time.Millisecond) {
+		t.Fatal("b should not acquire while a keeps renewing")
+	}
+	select {
+	case <-a.Lost():
+		t.Fatal("lease should not be lost")
+	default:
+	}
+	a.Unlock()
+	if !b.TryLock() {
+		t.Fatal("b should acquire after a unlocked")
+	}
+	b.Unlock()
+}
+
+func TestDLockTinyTTL(t *testing.T) {
+	srv, addr := startLockServer(t)
+	// 不足1毫秒的租约按1毫秒处理，续租的间隔不会变成0
+	a := dialLockServer(t, addr).NewLocker("tiny", dlock.WithTTL(time.Nanosecond))
+	a.Lock()
+	time.Sleep(10 * time.Millisecond)
+	if _, ok := srv.Holder("tiny"); !ok {
+		select {
+		case <-a.Lost():
+		default:
+			t.Fatal("lease expired without notifying the holder")
+		}
+	}
+	a.Unlock()
+}
+
+// stallConn 调用stall后写入会阻塞，模拟客户端失去响应
+type stallConn struct {
+	net.Conn
+	stalled atomic.Bool
+	resume  chan struct{}
+}
+
+func (c *stallConn) Write(b []byte) (int, error) {
+	if c.stalled.Load() {
+		<-c.resume
+	}
+	return c.Conn.Write(b)
+}
+
+func TestDLockLeaseExpire(t *testing.T) {
+	srv, addr := startLockServer(t)
+	conn, err := netx.CreateTcpConn(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := &stallConn{Conn: conn, resume: make(chan struct{})}
+	ca := dlock.NewClient(sc)
+	t.Cleanup(func() {
+		close(sc.resume)
+		_ = ca.Close()
+	})
+	a := ca.NewLocker("lease", dlock.WithTTL(60*time.Millisecond))
+	b := dialLockServer(t, addr).NewLocker("lease", dlock.WithPollInterval(5*time.Millisecond))
+
+	a.Lock()
+	first := a.Token()
+	sc.stalled.Store(true)
+
+	select {
+	case <-a.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("a should be notified that the lease is lost")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.LockContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer b.Unlock()
+	if token, ok := srv.Holder("lease"); !ok || token != b.Token() || token <= first {
+		t.Fatalf("holder=%d,%v b=%d a=%d", token, ok, b.Token(), first)
+	}
+}
+
+func TestDLockReleaseOnDisconnect(t *testing.T) {
+	srv, addr := startLockServer(t)
+	ca := dialLockServer(t, addr)
+	a := ca.NewLocker("conn")
+	b := dialLockServer(t, addr).NewLocker("conn", dlock.WithPollInterval(5*time.Millisecond))
+
+	a.Lock()
+	if _, ok := srv.Holder("conn"); !ok {
+		t.Fatal("lock should be held")
+	}
+	_ = ca.Close()
+	select {
+	case <-a.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("a should be notified when the connection is closed")
+	}
+	if !errors.Is(a.UnlockContext(context.Background()), dlock.ErrClientClosed) {
+		t.Fatal("unlock on a closed client should fail")
+	}
+	if !b.LockTimeout(time.Second) {
+		t.Fatal("b should acquire after a disconnected")
+	}
+	b.Unlock()
+}
+
+func TestDLockContext(t *testing.T) {
+	_, addr := startLockServer(t)
+	a := dialLockServer(t, addr).NewLocker("ctx")
+	b := dialLockServer(t, addr).NewLocker("ctx")
+
+	a.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := b.LockContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	if b.Token() != 0 || b.Lost() != nil {
+		t.Fatal("b should not hold the lock")
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- b.LockContext(context.Background()) }()
+	time.Sleep(20 * time.Millisecond)
+	a.Unlock()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("b should acquire after a unlocked")
+	}
+	b.Unlock()
+}
@@ -0,0 +1,156 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/yuhao-jack/go-toolx/lockx"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileLockExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.lock")
+	a, b := lockx.NewFileLock(path), lockx.NewFileLock(path)
+
+	a.Lock()
+	if !a.Locked() {
+		t.Fatal("a should hold the lock")
+	}
+	if b.TryLock() || b.TryRLock() {
+		t.Fatal("b should not acquire while a holds the exclusive lock")
+	}
+	if a.TryLock() || a.LockTimeout(10*time.Millisecond) {
+		t.Fatal("a is not reentrant and should not acquire again")
+	}
+	if pid, err := a.HolderPID(); err != nil || pid != os.Getpid() {
+		t.Fatalf("pid=%d err=%v", pid, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := b.LockContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		a.Unlock()
+	}()
+	if !b.LockTimeout(time.Second) {
+		t.Fatal("b should acquire after a released")
+	}
+	b.Unlock()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("lock file should be kept: %v", err)
+	}
+}
+
+func TestFileLockSharedBetweenGoroutines(t *testing.T) {
+	l := lockx.NewFileLock(filepath.Join(t.TempDir(), "app.lock"))
+	var counter, inside int32
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				l.Lock()
+				if atomic.AddInt32(&inside, 1) != 1 {
+					t.Error("two goroutines hold the exclusive lock at the same time")
+				}
+				counter++
+				atomic.AddInt32(&inside, -1)
+				l.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if counter != 80 || l.Locked() {
+		t.Fatalf("counter=%d locked=%v", counter, l.Locked())
+	}
+
+	// 多个goroutine可以同时持有共享锁，独占锁要等它们都释放
+	l.RLock()
+	if !l.RLockTimeout(time.Second) {
+		t.Fatal("shared locks from the same FileLock should not conflict")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.LockContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	l.RUnlock()
+	l.RUnlock()
+	if !l.TryLock() {
+		t.Fatal("exclusive lock should succeed after shared holders released")
+	}
+	l.Unlock()
+}
+
+func TestFileLockShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.lock")
+	a, b, w := lockx.NewFileLock(path), lockx.NewFileLock(path), lockx.NewFileLock(path)
+	if !a.TryRLock() || !b.RLockTimeout(time.Second) {
+		t.Fatal("shared locks should not conflict")
+	}
+	if w.TryLock() {
+		t.Fatal("exclusive lock should wait for shared holders")
+	}
+	a.RUnlock()
+	if w.TryLock() {
+		t.Fatal("exclusive lock should wait for all shared holders")
+	}
+	b.RUnlock()
+	if !w.TryLock() {
+		t.Fatal("exclusive lock should succeed after shared holders released")
+	}
+	w.Unlock()
+}
+
+func TestFileLockUnlockMismatch(t *testing.T) {
+	l := lockx.NewFileLock(filepath.Join(t.TempDir(), "app.lock"))
+	l.RLock()
+	defer l.RUnlock()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("unlock of a shared lock should panic")
+		}
+	}()
+	l.Unlock()
+}
+
+func TestFileLockReplacedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.lock")
+	a, b := lockx.NewFileLock(path), lockx.NewFileLock(path)
+	a.Lock()
+	defer a.Unlock()
+
+	// 锁文件被删除后，a锁住的是一个孤立的文件，新的加锁者会锁住新创建的文件
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if !b.TryLock() {
+		t.Fatal("b should lock the recreated file")
+	}
+	b.Unlock()
+}
+
+func TestFileLockError(t *testing.T) {
+	l := lockx.NewFileLock(filepath.Join(t.TempDir(), "missing", "app.lock"))
+	if err := l.LockContext(context.Background()); err == nil {
+		t.Fatal("lock in a missing directory should fail")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Lock should panic on error")
+		}
+	}()
+	l.Lock()
+}
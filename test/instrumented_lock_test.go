@@ -0,0 +1,177 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/yuhao-jack/go-toolx/lockx"
+	"strings"
+	"testing"
+	"time"
+)
+
+func holdInstrumentedLock(l *lockx.InstrumentedLocker, d time.Duration) {
+	l.Lock()
+	l.Lock()
+	time.Sleep(d)
+	l.Unlock()
+	l.Unlock()
+}
+
+func TestInstrumentedLocker(t *testing.T) {
+	l := lockx.Instrument("test.instrumented", lockx.NewReentrantLock())
+	defer lockx.Unregister("test.instrumented")
+
+	holdInstrumentedLock(l, 20*time.Millisecond)
+
+	release := holdIn(l.Lock, l.Unlock)
+	if l.TryLock() {
+		t.Fatal("trylock of a held lock should fail")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := l.LockContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		release()
+	}()
+	l.Lock()
+	l.Unlock()
+
+	stats := l.Stats()
+	if stats.Name != "test.instrumented" || stats.Acquisitions != 4 || stats.Contended != 1 || stats.Failed != 2 {
+		t.Fatalf("stats=%+v", stats)
+	}
+	if stats.MaxHold < 20*time.Millisecond || stats.TotalHold < stats.MaxHold {
+		t.Fatalf("hold=%v total=%v", stats.MaxHold, stats.TotalHold)
+	}
+	if stats.MaxWait < 5*time.Millisecond || stats.TotalWait < stats.MaxWait {
+		t.Fatalf("wait=%v total=%v", stats.MaxWait, stats.TotalWait)
+	}
+	if !strings.Contains(stats.MaxHoldStack, "holdInstrumentedLock") {
+		t.Fatalf("stack should point to the longest holder:\n%s", stats.MaxHoldStack)
+	}
+
+	l.ResetStats()
+	if s := l.Stats(); s.Acquisitions != 0 || s.MaxHoldStack != "" || s.Name != "test.instrumented" {
+		t.Fatalf("stats=%+v", s)
+	}
+}
+
+func TestInstrumentedLockerBadUnlock(t *testing.T) {
+	l := lockx.Instrument("test.instrumented.bad", lockx.NewReentrantLock(), lockx.WithoutRegister())
+	l.Lock()
+	panicked := make(chan bool)
+	go func() {
+		defer func() { panicked <- recover() != nil }()
+		l.Unlock()
+	}()
+	if !<-panicked {
+		t.Fatal("unlock by a non-owner should panic")
+	}
+	if s := l.Stats(); s.TotalHold != 0 {
+		t.Fatalf("a failed unlock should not record hold time, stats=%+v", s)
+	}
+	time.Sleep(time.Millisecond)
+	l.Unlock()
+	if s := l.Stats(); s.TotalHold < time.Millisecond {
+		t.Fatalf("stats=%+v", s)
+	}
+	if !l.TryLock() {
+		t.Fatal("lock should be free after the owner unlocked")
+	}
+	l.Unlock()
+}
+
+func TestInstrumentedLockerBadUnlockConcurrent(t *testing.T) {
+	l := lockx.Instrument("test.instrumented.race", lockx.NewReentrantLock(), lockx.WithoutRegister())
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			func() {
+				defer func() { _ = recover() }()
+				l.Unlock()
+			}()
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		l.Lock()
+		l.Lock()
+		l.Unlock()
+		l.Unlock()
+	}
+	close(stop)
+	<-done
+	if s := l.Stats(); s.Acquisitions != 400 {
+		t.Fatalf("stats=%+v", s)
+	}
+	if !l.TryLock() {
+		t.Fatal("lock should be free")
+	}
+	l.Unlock()
+}
+
+func TestInstrumentedRWLocker(t *testing.T) {
+	l := lockx.InstrumentRW("test.instrumented.rw", lockx.NewReentrantRWMutex(), lockx.WithStackDepth(0))
+	defer lockx.Unregister("test.instrumented.rw")
+
+	release := holdIn(func() { l.RLock(); l.RLock() }, func() { l.RUnlock(); l.RUnlock() })
+	if l.TryLock() {
+		t.Fatal("writer should wait for readers")
+	}
+	release()
+	l.Lock()
+	if !l.TryRLock() {
+		t.Fatal("writer should re-enter read lock")
+	}
+	l.RUnlock()
+	l.Unlock()
+
+	stats := l.Stats()
+	if stats.ReadAcquisitions != 3 || stats.Acquisitions != 1 || stats.Failed != 1 || stats.MaxHoldStack != "" {
+		t.Fatalf("stats=%+v", stats)
+	}
+}
+
+func TestInstrumentedSnapshot(t *testing.T) {
+	b := lockx.Instrument("test.snapshot.b", lockx.NewReentrantLock())
+	a := lockx.Instrument("test.snapshot.a", lockx.NewReentrantLock())
+	hidden := lockx.Instrument("test.snapshot.hidden", lockx.NewReentrantLock(), lockx.WithoutRegister())
+	defer lockx.Unregister("test.snapshot.a")
+	defer lockx.Unregister("test.snapshot.b")
+	a.Lock()
+	a.Unlock()
+	b.Lock()
+	b.Unlock()
+	hidden.Lock()
+	hidden.Unlock()
+
+	var names []string
+	for _, s := range lockx.Snapshot() {
+		if strings.HasPrefix(s.Name, "test.snapshot.") {
+			names = append(names, s.Name)
+		}
+	}
+	if strings.Join(names, ",") != "test.snapshot.a,test.snapshot.b" {
+		t.Fatalf("names=%v", names)
+	}
+
+	data, err := json.Marshal(lockx.Snapshot())
+	if err != nil || !strings.Contains(string(data), `"name":"test.snapshot.a"`) {
+		t.Fatalf("data=%s err=%v", data, err)
+	}
+
+	lockx.ResetStats()
+	if a.Stats().Acquisitions != 0 || hidden.Stats().Acquisitions != 1 {
+		t.Fatal("reset should only affect registered locks")
+	}
+}
@@ -0,0 +1,150 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/yuhao-jack/go-toolx/lockx"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyedMutexIndependentKeys(t *testing.T) {
+	m := lockx.NewKeyedMutex[string]()
+	m.Lock("alice")
+	if !m.TryLock("bob") {
+		t.Fatal("different keys should not block each other")
+	}
+	if m.TryLock("alice") || m.TryRLock("alice") {
+		t.Fatal("a locked key should not be acquired again")
+	}
+	if m.Len() != 2 {
+		t.Fatalf("len=%d", m.Len())
+	}
+	m.Unlock("alice")
+	m.Unlock("bob")
+	if m.Len() != 0 {
+		t.Fatalf("entries should be freed after unlock, len=%d", m.Len())
+	}
+}
+
+func TestKeyedMutexReaders(t *testing.T) {
+	m := lockx.NewKeyedMutex[int]()
+	m.RLock(1)
+	if !m.TryRLock(1) {
+		t.Fatal("readers should share a key")
+	}
+	if m.LockTimeout(1, 20*time.Millisecond) {
+		t.Fatal("writer should wait for readers")
+	}
+	m.RUnlock(1)
+	m.RUnlock(1)
+	if !m.TryLock(1) {
+		t.Fatal("writer should acquire after readers released")
+	}
+	if m.RLockTimeout(1, 20*time.Millisecond) {
+		t.Fatal("reader should wait for the writer")
+	}
+	m.Unlock(1)
+	if m.Len() != 0 {
+		t.Fatalf("len=%d", m.Len())
+	}
+}
+
+func TestKeyedMutexContext(t *testing.T) {
+	m := lockx.NewKeyedMutex[string]()
+	m.Lock("k")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := m.LockContext(ctx, "k"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	if err := m.RLockContext(ctx, "k"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	if m.Len() != 1 {
+		t.Fatalf("canceled waiters should drop their reference, len=%d", m.Len())
+	}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		m.Unlock("k")
+	}()
+	if err := m.LockContext(context.Background(), "k"); err != nil {
+		t.Fatal(err)
+	}
+	m.Unlock("k")
+}
+
+func TestKeyedMutexUnlockUnlocked(t *testing.T) {
+	m := lockx.NewKeyedMutex[string]()
+	for name, fx := range map[string]func(string){"Unlock": m.Unlock, "RUnlock": m.RUnlock} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s of an unlocked key should panic", name)
+				}
+			}()
+			fx("x")
+		}()
+	}
+}
+
+func TestStripedKeyedMutex(t *testing.T) {
+	m := lockx.NewStripedKeyedMutex[int](3)
+	if m.Len() != 4 {
+		t.Fatalf("stripes should round up to a power of two, len=%d", m.Len())
+	}
+	m.Lock(1)
+	if m.TryLock(1) {
+		t.Fatal("a locked key should not be acquired again")
+	}
+	m.Unlock(1)
+
+	one := lockx.NewStripedKeyedMutex[int](1)
+	one.Lock(1)
+	if one.TryLock(2) {
+		t.Fatal("keys in the same stripe should be mutually exclusive")
+	}
+	one.Unlock(1)
+}
+
+func TestKeyedMutexConcurrent(t *testing.T) {
+	for name, m := range map[string]*lockx.KeyedMutex[int]{
+		"refcount": lockx.NewKeyedMutex[int](),
+		"striped":  lockx.NewStripedKeyedMutex[int](4),
+	} {
+		t.Run(name, func(t *testing.T) {
+			counters := make([]int, 8)
+			var wg sync.WaitGroup
+			for i := 0; i < 32; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					for j := 0; j < 200; j++ {
+						key := (i + j) % len(counters)
+						if j%4 == 0 {
+							m.RLock(key)
+							_ = counters[key]
+							m.RUnlock(key)
+							continue
+						}
+						m.Lock(key)
+						counters[key]++
+						m.Unlock(key)
+					}
+				}(i)
+			}
+			wg.Wait()
+			total := 0
+			for _, c := range counters {
+				total += c
+			}
+			if total != 32*150 {
+				t.Fatalf("total=%d", total)
+			}
+			if name == "refcount" && m.Len() != 0 {
+				t.Fatalf("len=%d", m.Len())
+			}
+		})
+	}
+}
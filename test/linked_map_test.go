@@ -0,0 +1,59 @@
+package test
+
+import (
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"reflect"
+	"testing"
+)
+
+func TestLinkedMapInsertionOrder(t *testing.T) {
+	m := containerx.NewLinkedMap[string, int]()
+	for i, k := range []string{"c", "a", "d", "b"} {
+		m.Set(k, i)
+	}
+	m.Set("a", 10)
+	m.Get("c")
+	m.Remove("d")
+	if keys := m.Keys(); !reflect.DeepEqual(keys, []string{"c", "a", "b"}) {
+		t.Fatalf("Keys = %v", keys)
+	}
+	if vals := m.Values(); !reflect.DeepEqual(vals, []int{0, 10, 3}) {
+		t.Fatalf("Values = %v", vals)
+	}
+	if k, v, ok := m.PopFront(); !ok || k != "c" || v != 0 {
+		t.Fatalf("PopFront = %v,%v,%v", k, v, ok)
+	}
+}
+
+func TestLinkedMapAccessOrder(t *testing.T) {
+	m := containerx.NewAccessOrderLinkedMap[string, int]()
+	for i, k := range []string{"a", "b", "c"} {
+		m.Set(k, i)
+	}
+	m.Get("a")
+	m.Set("b", 5)
+	m.Peek("c")
+	if keys := m.Keys(); !reflect.DeepEqual(keys, []string{"c", "a", "b"}) {
+		t.Fatalf("Keys = %v", keys)
+	}
+	if k, _, _ := m.Back(); k != "b" {
+		t.Fatalf("Back = %v", k)
+	}
+}
+
+func TestOrderedSet(t *testing.T) {
+	s := containerx.NewOrderedSetOf(5, 3, 9, 3, 1)
+	s.Remove(9)
+	if elements := s.Elements(); !reflect.DeepEqual(elements, []int{5, 3, 1}) {
+		t.Fatalf("Elements = %v", elements)
+	}
+	odd := s.Filter(func(item int) bool { return item != 3 })
+	if elements := odd.Elements(); !reflect.DeepEqual(elements, []int{5, 1}) {
+		t.Fatalf("Filter = %v", elements)
+	}
+
+	a := containerx.NewAccessOrderSet[int]().Add(1).Add(2).Add(1)
+	if first, _ := a.First(); first != 2 {
+		t.Fatalf("First = %d", first)
+	}
+}
@@ -0,0 +1,61 @@
+package test
+
+import (
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"reflect"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	var l containerx.List[int]
+	two := l.PushBack(2)
+	l.PushFront(1)
+	four := l.PushBack(4)
+	l.InsertBefore(3, four)
+	l.MoveToFront(four)
+	l.MoveAfter(four, two)
+	if values := l.Values(); !reflect.DeepEqual(values, []int{1, 2, 4, 3}) {
+		t.Fatalf("Values = %v", values)
+	}
+	if v := l.Remove(two); v != 2 || l.Len() != 3 {
+		t.Fatalf("Remove = %d, Len = %d", v, l.Len())
+	}
+	l.Remove(two)
+	if l.Len() != 3 || l.Back().Value != 3 || l.Front().Prev() != nil {
+		t.Fatalf("removing twice should be a no-op, Len = %d", l.Len())
+	}
+}
+
+func TestDeque(t *testing.T) {
+	d := containerx.NewDeque[int]()
+	for i := 0; i < 10; i++ {
+		d.PushBack(i)
+		d.PushFront(-i - 1)
+	}
+	if d.Len() != 20 {
+		t.Fatalf("Len = %d", d.Len())
+	}
+	if v, ok := d.At(0); !ok || v != -10 {
+		t.Fatalf("At(0) = %d,%v", v, ok)
+	}
+	if v, ok := d.At(19); !ok || v != 9 {
+		t.Fatalf("At(19) = %d,%v", v, ok)
+	}
+	if _, ok := d.At(20); ok {
+		t.Fatal("At out of range should fail")
+	}
+	if v, _ := d.PopFront(); v != -10 {
+		t.Fatalf("PopFront = %d", v)
+	}
+	if v, _ := d.PopBack(); v != 9 {
+		t.Fatalf("PopBack = %d", v)
+	}
+	want := []int{-9, -8, -7, -6, -5, -4, -3, -2, -1, 0, 1, 2, 3, 4, 5, 6, 7, 8}
+	if values := d.Values(); !reflect.DeepEqual(values, want) {
+		t.Fatalf("Values = %v", values)
+	}
+	d.Clear()
+	if _, ok := d.PopBack(); ok || !d.IsEmpty() {
+		t.Fatal("PopBack on an empty deque should fail")
+	}
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"github.com/yuhao-jack/go-toolx/lockx"
+	"sync"
+	"testing"
+)
+
+// 对比各种锁一次加锁+解锁的开销：
+//	go test -bench=Lock -benchmem ./test/
+
+func BenchmarkLockSyncMutex(b *testing.B) {
+	var m sync.Mutex
+	for i := 0; i < b.N; i++ {
+		m.Lock()
+		m.Unlock()
+	}
+}
+
+func BenchmarkLockGetGoroutineID(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		lockx.GetGoroutineID()
+	}
+}
+
+func BenchmarkLockReentrantMutex(b *testing.B) {
+	var m lockx.ReentrantMutex
+	for i := 0; i < b.N; i++ {
+		m.Lock()
+		m.Unlock()
+	}
+}
+
+func BenchmarkLockReentrantMutexOwner(b *testing.B) {
+	var m lockx.ReentrantMutex
+	owner := lockx.NewOwner()
+	for i := 0; i < b.N; i++ {
+		m.LockAs(owner)
+		m.UnlockAs(owner)
+	}
+}
+
+func BenchmarkLockSyncRWMutexRead(b *testing.B) {
+	var m sync.RWMutex
+	for i := 0; i < b.N; i++ {
+		m.RLock()
+		m.RUnlock()
+	}
+}
+
+func BenchmarkLockReentrantRWMutexRead(b *testing.B) {
+	var m lockx.ReentrantRWMutex
+	for i := 0; i < b.N; i++ {
+		m.RLock()
+		m.RUnlock()
+	}
+}
+
+func BenchmarkLockReentrantRWMutexReadOwner(b *testing.B) {
+	var m lockx.ReentrantRWMutex
+	owner := lockx.NewOwner()
+	for i := 0; i < b.N; i++ {
+		m.RLockAs(owner)
+		m.RUnlockAs(owner)
+	}
+}
+
+func BenchmarkLockReentrantMutexParallel(b *testing.B) {
+	var m lockx.ReentrantMutex
+	b.RunParallel(func(pb *testing.PB) {
+		owner := lockx.NewOwner()
+		for pb.Next() {
+			m.LockAs(owner)
+			m.UnlockAs(owner)
+		}
+	})
+}
+
+func BenchmarkLockSyncMutexParallel(b *testing.B) {
+	var m sync.Mutex
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Lock()
+			m.Unlock()
+		}
+	})
+}
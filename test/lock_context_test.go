@@ -0,0 +1,144 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/yuhao-jack/go-toolx/lockx"
+	"testing"
+	"time"
+)
+
+// holdIn 在新的goroutine中调用lock并一直持有，直到返回的函数被调用
+func holdIn(lock, unlock func()) (release func()) {
+	locked, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		lock()
+		close(locked)
+		<-done
+		unlock()
+	}()
+	<-locked
+	return func() { close(done) }
+}
+
+func TestContextLockers(t *testing.T) {
+	lockers := map[string]lockx.ContextLocker{
+		"ReentrantMutex":   lockx.NewReentrantLock(),
+		"ReentrantRWMutex": lockx.NewReentrantRWMutex(),
+	}
+	for name, l := range lockers {
+		t.Run(name, func(t *testing.T) {
+			if !l.TryLock() || !l.TryLock() {
+				t.Fatal("trylock of a free lock and reentry should succeed")
+			}
+			l.Unlock()
+			l.Unlock()
+
+			release := holdIn(l.Lock, l.Unlock)
+			if l.TryLock() {
+				t.Fatal("trylock of a held lock should fail")
+			}
+			start := time.Now()
+			if l.LockTimeout(30 * time.Millisecond) {
+				t.Fatal("lock timeout should fail")
+			}
+			if time.Since(start) < 30*time.Millisecond {
+				t.Fatal("lock timeout returned too early")
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				cancel()
+			}()
+			if err := l.LockContext(ctx); !errors.Is(err, context.Canceled) {
+				t.Fatalf("err=%v", err)
+			}
+
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				release()
+			}()
+			if !l.LockTimeout(time.Second) {
+				t.Fatal("lock should be acquired after release")
+			}
+			l.Unlock()
+			if err := l.LockContext(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			l.Unlock()
+		})
+	}
+}
+
+func TestReentrantRWMutexReadContext(t *testing.T) {
+	var m lockx.ReentrantRWMutex
+	if !m.TryRLock() || !m.RLockTimeout(0) {
+		t.Fatal("read lock of a free lock should succeed")
+	}
+	m.RUnlock()
+	m.RUnlock()
+
+	release := holdIn(m.Lock, m.Unlock)
+	if m.TryRLock() || m.RLockTimeout(20*time.Millisecond) {
+		t.Fatal("read lock should fail while a writer holds the lock")
+	}
+	release()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := m.RLockContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	m.RUnlock()
+}
+
+func TestReentrantRWMutexCanceledWriterUnblocksReaders(t *testing.T) {
+	var m lockx.ReentrantRWMutex
+	release := holdIn(m.RLock, m.RUnlock)
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	writerErr := make(chan error)
+	go func() { writerErr <- m.LockContext(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+
+	// 有写者等待，新的读者需要排队
+	readerDone := make(chan struct{})
+	go func() {
+		m.RLock()
+		m.RUnlock()
+		close(readerDone)
+	}()
+	select {
+	case <-readerDone:
+		t.Fatal("reader should queue behind the waiting writer")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-writerErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("err=%v", err)
+	}
+	select {
+	case <-readerDone:
+	case <-time.After(time.Second):
+		t.Fatal("reader should proceed after the writer gave up")
+	}
+}
+
+func TestReentrantMutexOwnerContext(t *testing.T) {
+	var m lockx.ReentrantMutex
+	a, b := lockx.NewOwner(), lockx.NewOwner()
+	if !m.TryLockAs(a) || m.TryLockAs(b) {
+		t.Fatal("unexpected trylock result")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := m.LockContextAs(ctx, b); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	m.UnlockAs(a)
+	if err := m.LockContextAs(context.Background(), b); err != nil {
+		t.Fatal(err)
+	}
+	m.UnlockAs(b)
+}
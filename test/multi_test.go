@@ -0,0 +1,65 @@
+package test
+
+import (
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"reflect"
+	"testing"
+)
+
+func TestMultiSet(t *testing.T) {
+	s := containerx.NewMultiSetOf("a", "b", "a", "c", "a")
+	if s.Count("a") != 3 || s.Size() != 5 || s.DistinctSize() != 3 {
+		t.Fatalf("Count(a) = %d, Size = %d, DistinctSize = %d", s.Count("a"), s.Size(), s.DistinctSize())
+	}
+	s.Remove("a").RemoveN("c", 5)
+	if s.Count("a") != 2 || s.Contains("c") || s.Size() != 3 {
+		t.Fatalf("Count(a) = %d, Size = %d", s.Count("a"), s.Size())
+	}
+	if !s.ToSet().Equal(containerx.NewSetOf("a", "b")) {
+		t.Fatalf("ToSet = %v", s.ToSet().Elements())
+	}
+	if len(s.Elements()) != 3 {
+		t.Fatalf("Elements = %v", s.Elements())
+	}
+}
+
+func TestBiMap(t *testing.T) {
+	m := containerx.NewBiMap[string, int]()
+	if err := m.Put("a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Put("b", 1); err != containerx.ErrDuplicateValue {
+		t.Fatalf("Put duplicate value = %v", err)
+	}
+	m.Put("a", 2)
+	if m.ContainsValue(1) || m.Size() != 1 {
+		t.Fatal("replacing the value of a key should drop the old inverse entry")
+	}
+	m.ForcePut("b", 2)
+	if m.ContainsKey("a") {
+		t.Fatal("ForcePut should remove the key previously bound to the value")
+	}
+	if k, ok := m.Inverse().Get(2); !ok || k != "b" {
+		t.Fatalf("Inverse().Get(2) = %s,%v", k, ok)
+	}
+	if k, ok := m.RemoveValue(2); !ok || k != "b" || !m.IsEmpty() {
+		t.Fatalf("RemoveValue = %s,%v", k, ok)
+	}
+}
+
+func TestMultiMap(t *testing.T) {
+	m := containerx.NewMultiMap[string, int]()
+	m.Put("a", 1).Put("a", 2).Put("a", 1).PutAll("b", 3, 4)
+	if !reflect.DeepEqual(m.Get("a"), []int{1, 2, 1}) || m.Size() != 5 || m.KeySize() != 2 {
+		t.Fatalf("Get(a) = %v, Size = %d", m.Get("a"), m.Size())
+	}
+	if !m.Remove("a", 1) || !reflect.DeepEqual(m.Get("a"), []int{2, 1}) {
+		t.Fatalf("Get(a) after Remove = %v", m.Get("a"))
+	}
+	if !m.ContainsEntry("b", 4) || m.ContainsEntry("b", 1) {
+		t.Fatal("ContainsEntry mismatch")
+	}
+	if vals := m.RemoveAll("b"); len(vals) != 2 || m.Size() != 2 || m.ContainsKey("b") {
+		t.Fatalf("RemoveAll = %v, Size = %d", vals, m.Size())
+	}
+}
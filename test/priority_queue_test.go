@@ -0,0 +1,70 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"testing"
+	"time"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := containerx.NewPriorityQueue(func(a, b int) bool { return a < b })
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		q.Push(v)
+	}
+	item := q.Push(10)
+	if !q.Update(item, 0) {
+		t.Fatal("Update failed")
+	}
+	removed := q.Push(-1)
+	if v, ok := q.Remove(removed); !ok || v != -1 {
+		t.Fatalf("Remove = %d,%v", v, ok)
+	}
+	if q.Fix(removed) {
+		t.Fatal("Fix on a removed item should fail")
+	}
+	if v, _ := q.Peek(); v != 0 {
+		t.Fatalf("Peek = %d", v)
+	}
+	var got []int
+	for !q.IsEmpty() {
+		v, _ := q.Pop()
+		got = append(got, v)
+	}
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("Pop order = %v", got)
+		}
+	}
+}
+
+func TestBlockingPriorityQueue(t *testing.T) {
+	q := containerx.NewBlockingPriorityQueue(func(a, b time.Duration) bool { return a < b })
+	done := make(chan time.Duration)
+	go func() {
+		v, err := q.Take(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		done <- v
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if _, err := q.Push(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if v := <-done; v != time.Second {
+		t.Fatalf("Take = %v", v)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Take on empty queue = %v", err)
+	}
+
+	q.Close()
+	if _, err := q.Take(context.Background()); !errors.Is(err, containerx.ErrQueueClosed) {
+		t.Fatalf("Take on closed queue = %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestRadixTreeRandom(t *testing.T) {
+	tree := containerx.NewRadixTree[string, int]()
+	ref := map[string]int{}
+	letters := "abc"
+	for i := 0; i < 5000; i++ {
+		b := make([]byte, rand.Intn(6))
+		for j := range b {
+			b[j] = letters[rand.Intn(len(letters))]
+		}
+		k := string(b)
+		if rand.Intn(3) == 0 {
+			_, ok := tree.Delete(k)
+			if _, want := ref[k]; ok != want {
+				t.Fatalf("Delete(%q) = %v, want %v", k, ok, want)
+			}
+			delete(ref, k)
+		} else {
+			tree.Insert(k, i)
+			ref[k] = i
+		}
+	}
+	keys := make([]string, 0, len(ref))
+	for k, v := range ref {
+		keys = append(keys, k)
+		if got, ok := tree.Get(k); !ok || got != v {
+			t.Fatalf("Get(%q) = %d,%v want %d", k, got, ok, v)
+		}
+	}
+	sort.Strings(keys)
+	if tree.Len() != len(keys) || !reflect.DeepEqual(tree.Keys(), keys) {
+		t.Fatalf("Keys = %v, want %v", tree.Keys(), keys)
+	}
+}
+
+func TestRadixTreePrefix(t *testing.T) {
+	tree := containerx.NewRadixTree[[]byte, string]()
+	for _, route := range []string{"/", "/user", "/user/info", "/order", "/user/list"} {
+		tree.Insert([]byte(route), route)
+	}
+	if k, v, ok := tree.LongestPrefix([]byte("/user/info/detail")); !ok || string(k) != "/user/info" || v != "/user/info" {
+		t.Fatalf("LongestPrefix = %s,%s,%v", k, v, ok)
+	}
+	if k, _, ok := tree.LongestPrefix([]byte("/ord")); !ok || string(k) != "/" {
+		t.Fatalf("LongestPrefix = %s,%v", k, ok)
+	}
+	var under []string
+	tree.WalkPrefix([]byte("/us"), func(key []byte, val string) bool {
+		under = append(under, val)
+		return true
+	})
+	if !reflect.DeepEqual(under, []string{"/user", "/user/info", "/user/list"}) {
+		t.Fatalf("WalkPrefix = %v", under)
+	}
+}
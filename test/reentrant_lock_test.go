@@ -0,0 +1,163 @@
+package test
+
+import (
+	"github.com/yuhao-jack/go-toolx/lockx"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReentrantLockNested(t *testing.T) {
+	l := lockx.NewReentrantLock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Lock()
+		l.Lock()
+		l.Lock()
+		l.Unlock()
+		l.Unlock()
+		l.Unlock()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("nested lock deadlocked")
+	}
+}
+
+func TestReentrantLockReleasesAfterLastUnlock(t *testing.T) {
+	var m lockx.ReentrantMutex
+	m.Lock()
+	m.Lock()
+
+	acquired := make(chan struct{})
+	go func() {
+		m.Lock()
+		close(acquired)
+		m.Unlock()
+	}()
+
+	m.Unlock()
+	select {
+	case <-acquired:
+		t.Fatal("lock released before the last unlock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	m.Unlock()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("lock not released after the last unlock")
+	}
+}
+
+func TestReentrantLockWrongOwnerUnlock(t *testing.T) {
+	var m lockx.ReentrantMutex
+	m.Lock()
+	defer m.Unlock()
+
+	panicked := make(chan bool)
+	go func() {
+		defer func() {
+			panicked <- recover() != nil
+		}()
+		m.Unlock()
+	}()
+	if !<-panicked {
+		t.Fatal("unlock by a non-owner goroutine should panic")
+	}
+}
+
+func TestReentrantLockUnlockWithoutLock(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("unlock of an unlocked mutex should panic")
+		}
+	}()
+	lockx.NewReentrantLock().Unlock()
+}
+
+func TestReentrantLockMutualExclusion(t *testing.T) {
+	var (
+		l       sync.Locker = lockx.NewReentrantLock()
+		wg      sync.WaitGroup
+		counter int
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Lock()
+				l.Lock()
+				counter++
+				l.Unlock()
+				l.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if counter != 5000 {
+		t.Fatalf("counter=%d", counter)
+	}
+}
+
+func TestReentrantLockOwner(t *testing.T) {
+	var m lockx.ReentrantMutex
+	a, b := lockx.NewOwner(), lockx.NewOwner()
+	if a == b || a >= 0 || b >= 0 {
+		t.Fatalf("owners should be distinct negative values: %d %d", a, b)
+	}
+	m.LockAs(a)
+	m.LockAs(a)
+
+	// 所有权可以跨goroutine传递
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.UnlockAs(a)
+	}()
+	<-done
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("unlock by another owner should panic")
+			}
+		}()
+		m.UnlockAs(b)
+	}()
+	m.UnlockAs(a)
+	if !acquiredWithin(time.Second, func() { m.LockAs(b) }, func() { m.UnlockAs(b) }) {
+		t.Fatal("lock not released")
+	}
+}
+
+func TestReentrantRWMutexOwner(t *testing.T) {
+	var m lockx.ReentrantRWMutex
+	w, r := lockx.NewOwner(), lockx.NewOwner()
+	m.LockAs(w)
+	m.RLockAs(w)
+	m.UnlockAs(w)
+	m.RLockAs(r)
+	m.RUnlockAs(w)
+	m.RUnlockAs(r)
+	if !acquiredWithin(time.Second, m.Lock, m.Unlock) {
+		t.Fatal("lock not released")
+	}
+}
+
+func TestGetGoroutineID(t *testing.T) {
+	ids := make(chan int64, 2)
+	for i := 0; i < 2; i++ {
+		go func() { ids <- lockx.GetGoroutineID() }()
+	}
+	a, b := <-ids, <-ids
+	if a <= 0 || b <= 0 || a == b {
+		t.Fatalf("ids=%d,%d", a, b)
+	}
+	if lockx.GetGoroutineID() != lockx.GetGoroutineID() {
+		t.Fatal("id should be stable within a goroutine")
+	}
+}
@@ -0,0 +1,182 @@
+package test
+
+import (
+	"github.com/yuhao-jack/go-toolx/lockx"
+	"sync"
+	"testing"
+	"time"
+)
+
+// acquiredWithin 在新的goroutine中调用lock，返回是否在d内获得了锁，获得后立即调用unlock释放
+func acquiredWithin(d time.Duration, lock, unlock func()) bool {
+	acquired := make(chan struct{})
+	go func() {
+		lock()
+		close(acquired)
+		unlock()
+	}()
+	select {
+	case <-acquired:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+func TestReentrantRWMutexWriterReentry(t *testing.T) {
+	m := lockx.NewReentrantRWMutex()
+	m.Lock()
+	m.Lock()
+	m.RLock()
+	m.RLock()
+	m.RUnlock()
+	m.RUnlock()
+	m.Unlock()
+	if acquiredWithin(50*time.Millisecond, m.RLock, m.RUnlock) {
+		t.Fatal("write lock released before the last unlock")
+	}
+	m.Unlock()
+	if !acquiredWithin(time.Second, m.Lock, m.Unlock) {
+		t.Fatal("write lock not released")
+	}
+}
+
+func TestReentrantRWMutexReaderReentry(t *testing.T) {
+	var m lockx.ReentrantRWMutex
+	m.RLock()
+	if !acquiredWithin(time.Second, m.RLock, m.RUnlock) {
+		t.Fatal("readers should share the lock")
+	}
+
+	// 有写者等待时，已持有读锁的goroutine重入不能被阻塞
+	writerDone := make(chan struct{})
+	go func() {
+		m.Lock()
+		m.Unlock()
+		close(writerDone)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	m.RLock()
+	if acquiredWithin(50*time.Millisecond, m.RLock, m.RUnlock) {
+		t.Fatal("new readers should queue behind a waiting writer")
+	}
+	m.RUnlock()
+	m.RUnlock()
+	select {
+	case <-writerDone:
+	case <-time.After(time.Second):
+		t.Fatal("writer not woken after readers released")
+	}
+}
+
+func TestReentrantRWMutexDowngrade(t *testing.T) {
+	var m lockx.ReentrantRWMutex
+	m.Lock()
+	m.RLock()
+	m.Unlock()
+	if !acquiredWithin(time.Second, m.RLock, m.RUnlock) {
+		t.Fatal("other readers should proceed after downgrade")
+	}
+	if acquiredWithin(50*time.Millisecond, m.Lock, m.Unlock) {
+		t.Fatal("writer should wait for the downgraded reader")
+	}
+	m.RUnlock()
+}
+
+func TestReentrantRWMutexUpgradePanics(t *testing.T) {
+	var m lockx.ReentrantRWMutex
+	m.RLock()
+	defer m.RUnlock()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("upgrade should panic")
+		}
+	}()
+	m.Lock()
+}
+
+func TestReentrantRWMutexTryUpgradeRecover(t *testing.T) {
+	var m lockx.ReentrantRWMutex
+	owner := lockx.NewOwner()
+	upgrade := func(lock, unlock func(), try func() bool) {
+		lock()
+		defer unlock()
+		defer func() {
+			if recover() == nil {
+				t.Fatal("try lock while holding a read lock should panic")
+			}
+		}()
+		try()
+	}
+	// panic之后锁仍然可以正常使用，内部的互斥锁没有被释放时这里会卡住
+	noop := func() {}
+	if !acquiredWithin(time.Second, func() { upgrade(m.RLock, m.RUnlock, m.TryLock) }, noop) {
+		t.Fatal("TryLock upgrade panic left the mutex locked")
+	}
+	if !acquiredWithin(time.Second, func() {
+		upgrade(func() { m.RLockAs(owner) }, func() { m.RUnlockAs(owner) }, func() bool { return m.TryLockAs(owner) })
+	}, noop) {
+		t.Fatal("TryLockAs upgrade panic left the mutex locked")
+	}
+	if !m.TryLock() {
+		t.Fatal("lock should be free")
+	}
+	m.Unlock()
+}
+
+func TestReentrantRWMutexWrongOwner(t *testing.T) {
+	var m lockx.ReentrantRWMutex
+	m.Lock()
+	defer m.Unlock()
+	panicked := make(chan bool)
+	go func() {
+		defer func() { panicked <- recover() != nil }()
+		m.Unlock()
+	}()
+	if !<-panicked {
+		t.Fatal("unlock by a non-owner goroutine should panic")
+	}
+	go func() {
+		defer func() { panicked <- recover() != nil }()
+		m.RUnlock()
+	}()
+	if !<-panicked {
+		t.Fatal("runlock without read lock should panic")
+	}
+}
+
+func TestReentrantRWMutexConcurrent(t *testing.T) {
+	var (
+		m       lockx.ReentrantRWMutex
+		wg      sync.WaitGroup
+		counter int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				m.Lock()
+				m.RLock()
+				counter++
+				m.RUnlock()
+				m.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			l := m.RLocker()
+			for j := 0; j < 50; j++ {
+				l.Lock()
+				l.Lock()
+				_ = counter
+				l.Unlock()
+				l.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if counter != 1000 {
+		t.Fatalf("counter=%d", counter)
+	}
+}
@@ -0,0 +1,214 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/yuhao-jack/go-toolx/lockx"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSemaphoreWeighted(t *testing.T) {
+	s := lockx.NewSemaphore(5)
+	ctx := context.Background()
+	if err := s.Acquire(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if !s.TryAcquire(2) || s.TryAcquire(1) {
+		t.Fatal("unexpected try acquire result")
+	}
+	if s.Available() != 0 {
+		t.Fatalf("available=%d", s.Available())
+	}
+	s.Release(4)
+	if s.Available() != 4 {
+		t.Fatalf("available=%d", s.Available())
+	}
+	s.Release(1)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("releasing more than held should panic")
+		}
+	}()
+	s.Release(1)
+}
+
+func TestSemaphoreWeight(t *testing.T) {
+	s := lockx.NewSemaphore(1)
+	ctx := context.Background()
+	if err := s.Acquire(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	// 0个许可总是立即成功，不改变计数
+	if err := s.Acquire(ctx, 0); err != nil || !s.TryAcquire(0) {
+		t.Fatal("acquiring 0 should succeed while the semaphore is full")
+	}
+	s.Release(0)
+	if s.Available() != 0 {
+		t.Fatalf("available=%d", s.Available())
+	}
+
+	for name, fx := range map[string]func(){
+		"Acquire":    func() { _ = s.Acquire(ctx, -1) },
+		"TryAcquire": func() { s.TryAcquire(-1) },
+		"Release":    func() { s.Release(-1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s with a negative weight should panic", name)
+				}
+			}()
+			fx()
+		}()
+	}
+	if s.Available() != 0 || s.TryAcquire(1) {
+		t.Fatal("negative weights should not change the count")
+	}
+	s.Release(1)
+}
+
+func TestSemaphoreFIFO(t *testing.T) {
+	s := lockx.NewSemaphore(3)
+	s.TryAcquire(3)
+
+	order := make(chan int64, 2)
+	for _, n := range []int64{3, 1} {
+		n := n
+		waiting := s.Waiting()
+		go func() {
+			_ = s.Acquire(context.Background(), n)
+			order <- n
+		}()
+		for s.Waiting() == waiting {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// 释放1个许可后，队首需要3个，后面需要1个的不能插队
+	s.Release(1)
+	if s.TryAcquire(1) {
+		t.Fatal("try acquire should not jump the queue")
+	}
+	select {
+	case n := <-order:
+		t.Fatalf("waiter %d should not be woken yet", n)
+	case <-time.After(20 * time.Millisecond):
+	}
+	s.Release(2)
+	if n := <-order; n != 3 {
+		t.Fatalf("first woken=%d", n)
+	}
+	s.Release(1)
+	if n := <-order; n != 1 {
+		t.Fatalf("second woken=%d", n)
+	}
+}
+
+func TestSemaphoreCancel(t *testing.T) {
+	s := lockx.NewSemaphore(2)
+	s.TryAcquire(2)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Acquire(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	if s.Waiting() != 0 {
+		t.Fatalf("canceled waiter should leave the queue, waiting=%d", s.Waiting())
+	}
+
+	// 队首的等待者取消后，后面的等待者应该被唤醒
+	bigCtx, bigCancel := context.WithCancel(context.Background())
+	bigErr := make(chan error)
+	go func() { bigErr <- s.Acquire(bigCtx, 2) }()
+	for s.Waiting() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	small := make(chan error)
+	go func() { small <- s.Acquire(context.Background(), 1) }()
+	for s.Waiting() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	s.Release(1)
+	bigCancel()
+	if err := <-bigErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("err=%v", err)
+	}
+	select {
+	case err := <-small:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter behind a canceled one should be woken")
+	}
+}
+
+func TestSemaphoreResize(t *testing.T) {
+	s := lockx.NewSemaphore(1)
+	done := make(chan error)
+	go func() { done <- s.Acquire(context.Background(), 3) }()
+	for s.Waiting() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	s.Resize(3)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	s.Resize(1)
+	if s.Available() != -2 || s.Size() != 1 {
+		t.Fatalf("available=%d size=%d", s.Available(), s.Size())
+	}
+	s.Release(3)
+	if !s.TryAcquire(1) {
+		t.Fatal("shrunk semaphore should still allow its new size")
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := lockx.NewLimiter(3)
+	var running, peak, total int32
+	for i := 0; i < 20; i++ {
+		err := l.Go(context.Background(), func() {
+			cur := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if cur <= p || atomic.CompareAndSwapInt32(&peak, p, cur) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&total, 1)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.Wait()
+	if total != 20 || peak > 3 {
+		t.Fatalf("total=%d peak=%d", total, peak)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	block := make(chan struct{})
+	l2 := lockx.NewLimiter(1)
+	if !l2.TryGo(func() { defer wg.Done(); <-block }) {
+		t.Fatal("first try go should run")
+	}
+	if l2.TryGo(func() {}) {
+		t.Fatal("second try go should be rejected")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l2.Go(ctx, func() {}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	close(block)
+	wg.Wait()
+	l2.Wait()
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSetAlgebra(t *testing.T) {
+	a := containerx.NewSetOf(1, 2, 3, 4)
+	b := containerx.NewSetOf(3, 4, 5)
+
+	if !a.Union(b).Equal(containerx.NewSetOf(1, 2, 3, 4, 5)) {
+		t.Fatalf("Union = %v", a.Union(b).Elements())
+	}
+	if !a.Intersection(b).Equal(containerx.NewSetOf(3, 4)) {
+		t.Fatalf("Intersection = %v", a.Intersection(b).Elements())
+	}
+	if !a.Difference(b).Equal(containerx.NewSetOf(1, 2)) {
+		t.Fatalf("Difference = %v", a.Difference(b).Elements())
+	}
+	if !a.SymmetricDifference(b).Equal(containerx.NewSetOf(1, 2, 5)) {
+		t.Fatalf("SymmetricDifference = %v", a.SymmetricDifference(b).Elements())
+	}
+	if !containerx.NewSetOf(3, 4).IsSubset(a) || !a.IsSuperset(containerx.NewSetOf(3, 4)) || a.IsSubset(b) {
+		t.Fatal("IsSubset/IsSuperset mismatch")
+	}
+
+	c := a.Clone().Add(9)
+	if a.Contains(9) || !c.Contains(9) {
+		t.Fatal("Clone should not share storage")
+	}
+	if !a.Map(func(item int) int { return item % 2 }).Equal(containerx.NewSetOf(0, 1)) {
+		t.Fatal("Map mismatch")
+	}
+	if !containerx.MapSet(b, func(item int) bool { return item > 4 }).Equal(containerx.NewSetOf(true, false)) {
+		t.Fatal("MapSet mismatch")
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	data, err := json.Marshal(containerx.NewSet[string]())
+	if err != nil || string(data) != "[]" {
+		t.Fatalf("Marshal empty set = %s, %v", data, err)
+	}
+	data, err = json.Marshal(containerx.NewSetOf("a", "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s containerx.Set[string]
+	if err = json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Equal(containerx.NewSetOf("b", "a")) {
+		t.Fatalf("Unmarshal = %v", s.Elements())
+	}
+}
+
+func TestConcurrentSetAddIfAbsent(t *testing.T) {
+	s := containerx.NewConcurrentSet[int]()
+	var added int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if s.AddIfAbsent(j) {
+					atomic.AddInt32(&added, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if added != 100 || s.Size() != 100 {
+		t.Fatalf("added = %d, Size = %d", added, s.Size())
+	}
+	even := s.Filter(func(item int) bool { return item%2 == 0 })
+	if even.Size() != 50 || !even.Contains(42) || even.Contains(7) {
+		t.Fatalf("Filter Size = %d", even.Size())
+	}
+	if !s.ToSet().IsSuperset(even.ToSet()) {
+		t.Fatal("ToSet mismatch")
+	}
+}
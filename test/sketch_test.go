@@ -0,0 +1,115 @@
+package test
+
+import (
+	"encoding/binary"
+	"github.com/yuhao-jack/go-toolx/containerx/sketch"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	if _, err := sketch.NewHyperLogLog(3); err != sketch.ErrInvalidPrecision {
+		t.Fatalf("NewHyperLogLog(3) = %v", err)
+	}
+	a, _ := sketch.NewHyperLogLog(14)
+	b, _ := sketch.NewHyperLogLog(14)
+	for i := 0; i < 60000; i++ {
+		a.AddString("user-" + strconv.Itoa(i))
+	}
+	for i := 40000; i < 100000; i++ {
+		b.AddString("user-" + strconv.Itoa(i))
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := a.MarshalBinary()
+	var c sketch.HyperLogLog
+	if err := c.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if e := math.Abs(float64(c.Count())-100000) / 100000; e > 0.03 {
+		t.Fatalf("Count = %d, error %v", c.Count(), e)
+	}
+
+	small, _ := sketch.NewHyperLogLog(10)
+	for i := 0; i < 10; i++ {
+		small.AddString(strconv.Itoa(i)).AddString(strconv.Itoa(i))
+	}
+	if n := small.Count(); n != 10 {
+		t.Fatalf("small Count = %d", n)
+	}
+}
+
+func TestCountMinTopK(t *testing.T) {
+	node1 := sketch.NewTopK(3, sketch.NewCountMinSketchWithEstimates(0.001, 0.01, sketch.WithConservativeUpdate()))
+	node2 := sketch.NewTopK(3, sketch.NewCountMinSketchWithEstimates(0.001, 0.01, sketch.WithConservativeUpdate()))
+	for i := 0; i < 1000; i++ {
+		node1.Add("cmd-"+strconv.Itoa(i), 1)
+		node2.Add("cmd-"+strconv.Itoa(i), 1)
+	}
+	node1.Add("GET", 500)
+	node1.Add("SET", 300)
+	node2.Add("SET", 300)
+	node2.Add("DEL", 100)
+
+	data, err := node2.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var remote sketch.TopK
+	if err = remote.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err = node1.Merge(&remote); err != nil {
+		t.Fatal(err)
+	}
+	top := node1.List()
+	want := []string{"SET", "GET", "DEL"}
+	for i, hh := range top {
+		if hh.Item != want[i] {
+			t.Fatalf("List = %v", top)
+		}
+	}
+	if c := node1.Sketch().EstimateString("SET"); c < 600 {
+		t.Fatalf("Estimate(SET) = %d", c)
+	}
+	if node1.Sketch().Total() != 2000+500+600+100 {
+		t.Fatalf("Total = %d", node1.Sketch().Total())
+	}
+}
+
+func TestSketchUnmarshalCorrupt(t *testing.T) {
+	// width*depth*8溢出为0，25字节的数据不应通过校验
+	data := make([]byte, 25)
+	binary.BigEndian.PutUint64(data[0:], 1<<31)
+	binary.BigEndian.PutUint64(data[8:], 1<<30)
+	var c sketch.CountMinSketch
+	if err := c.UnmarshalBinary(data); err != sketch.ErrInvalidData {
+		t.Fatalf("CountMinSketch.UnmarshalBinary = %v", err)
+	}
+
+	wrapped := make([]byte, 8, 8+len(data)+4)
+	binary.BigEndian.PutUint32(wrapped[0:], 3)
+	binary.BigEndian.PutUint32(wrapped[4:], uint32(len(data)))
+	wrapped = append(append(wrapped, data...), 0, 0, 0, 0)
+	var top sketch.TopK
+	if err := top.UnmarshalBinary(wrapped); err != sketch.ErrInvalidData {
+		t.Fatalf("TopK.UnmarshalBinary = %v", err)
+	}
+}
+
+func TestHyperLogLogUnmarshalCorrupt(t *testing.T) {
+	h, _ := sketch.NewHyperLogLog(4)
+	h.AddString("a")
+	data, _ := h.MarshalBinary()
+	var g sketch.HyperLogLog
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	// 精度为4时寄存器最大为61，64会让1<<r溢出为0
+	data[1] = 64
+	if err := g.UnmarshalBinary(data); err != sketch.ErrInvalidData {
+		t.Fatalf("UnmarshalBinary = %v", err)
+	}
+}
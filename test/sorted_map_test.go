@@ -0,0 +1,85 @@
+package test
+
+import (
+	"github.com/yuhao-jack/go-toolx/containerx"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSortedMapRandom(t *testing.T) {
+	m := containerx.NewSortedMap[int, int]()
+	ref := map[int]int{}
+	for i := 0; i < 5000; i++ {
+		k := rand.Intn(1000)
+		if rand.Intn(3) == 0 {
+			_, ok := m.Remove(k)
+			_, want := ref[k]
+			if ok != want {
+				t.Fatalf("Remove(%d) = %v, want %v", k, ok, want)
+			}
+			delete(ref, k)
+		} else {
+			m.Set(k, i)
+			ref[k] = i
+		}
+	}
+	keys := make([]int, 0, len(ref))
+	for k := range ref {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	if m.Len() != len(keys) || !reflect.DeepEqual(m.Keys(), keys) {
+		t.Fatalf("Keys mismatch, Len = %d want %d", m.Len(), len(keys))
+	}
+	for i, k := range keys {
+		if r := m.Rank(k); r != i {
+			t.Fatalf("Rank(%d) = %d, want %d", k, r, i)
+		}
+		if sk, v, ok := m.Select(i); !ok || sk != k || v != ref[k] {
+			t.Fatalf("Select(%d) = %d,%d,%v want %d", i, sk, v, ok, k)
+		}
+	}
+	if _, _, ok := m.Select(len(keys)); ok {
+		t.Fatal("Select out of range should fail")
+	}
+}
+
+func TestSortedSetNavigation(t *testing.T) {
+	s := containerx.NewSortedSetOf(10, 20, 30, 40)
+	check := func(name string, got int, ok bool, want int, wantOk bool) {
+		if ok != wantOk || (ok && got != want) {
+			t.Fatalf("%s = %d,%v want %d,%v", name, got, ok, want, wantOk)
+		}
+	}
+	got, ok := s.Floor(25)
+	check("Floor(25)", got, ok, 20, true)
+	got, ok = s.Floor(5)
+	check("Floor(5)", got, ok, 0, false)
+	got, ok = s.Ceiling(30)
+	check("Ceiling(30)", got, ok, 30, true)
+	got, ok = s.Higher(30)
+	check("Higher(30)", got, ok, 40, true)
+	got, ok = s.Lower(10)
+	check("Lower(10)", got, ok, 0, false)
+	got, ok = s.Min()
+	check("Min", got, ok, 10, true)
+	got, ok = s.Max()
+	check("Max", got, ok, 40, true)
+
+	var between []int
+	s.Range(15, 40, func(item int) bool {
+		between = append(between, item)
+		return true
+	})
+	if !reflect.DeepEqual(between, []int{20, 30}) {
+		t.Fatalf("Range = %v", between)
+	}
+
+	desc := containerx.NewSortedSetFunc(func(a, b string) int { return containerx.CompareOrdered(b, a) })
+	desc.Add("a").Add("c").Add("b")
+	if elements := desc.Elements(); !reflect.DeepEqual(elements, []string{"c", "b", "a"}) {
+		t.Fatalf("Elements = %v", elements)
+	}
+}